@@ -0,0 +1,109 @@
+// grpc-gateway的runtime.Marshaler实现，选项与runtime.JSONPb相同，可以按路由替换
+//
+//	mux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard, &gateway.JSONPb{}))
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	fastjsonpb "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/json"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// message实现了FastJsonpb时使用生成的方法，输出与runtime.JSONPb等价（字段顺序、空白可能不同）
+// 其他类型交给runtime.JSONPb处理
+// 不支持AllowPartial、Resolver
+type JSONPb struct {
+	protojson.MarshalOptions
+	protojson.UnmarshalOptions
+}
+
+var _ runtime.Marshaler = (*JSONPb)(nil)
+
+// 总是返回"application/json"
+func (*JSONPb) ContentType(_ interface{}) string {
+	return "application/json"
+}
+
+func (j *JSONPb) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(fastjsonpb.FastJsonpb)
+	if !ok {
+		return j.fallback().Marshal(v)
+	}
+	ret, err := fastjsonpb.MarshalOptions{
+		UseProtoNames:   j.UseProtoNames,
+		UseEnumNumbers:  j.UseEnumNumbers,
+		EmitUnpopulated: j.EmitUnpopulated,
+		QuoteInt64:      true,
+		ProtojsonFloat:  true,
+	}.Marshal(m)
+	if err != nil || (!j.Multiline && j.Indent == "") {
+		return ret, err
+	}
+	// 与protojson一致，设置Indent时即为多行输出，默认缩进两个空格
+	indent := j.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, ret, "", indent); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (j *JSONPb) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(fastjsonpb.FastJsonpb)
+	if !ok {
+		return j.fallback().Unmarshal(data, v)
+	}
+	return j.unmarshal(data, m)
+}
+
+func (j *JSONPb) unmarshal(data []byte, m fastjsonpb.FastJsonpb) error {
+	return fastjsonpb.UnmarshalOptions{DiscardUnknown: j.DiscardUnknown, NullAsUnset: true}.Unmarshal(data, m)
+}
+
+// 从r中依次读取json，与runtime.JSONPb一致
+func (j *JSONPb) NewDecoder(r io.Reader) runtime.Decoder {
+	d := json.NewDecoder(r)
+	fallback := runtime.DecoderWrapper{Decoder: d, UnmarshalOptions: j.UnmarshalOptions}
+	return runtime.DecoderFunc(func(v interface{}) error {
+		m, ok := v.(fastjsonpb.FastJsonpb)
+		if !ok {
+			return fallback.Decode(v)
+		}
+		var raw json.RawMessage
+		if err := d.Decode(&raw); err != nil {
+			return err
+		}
+		return j.unmarshal(raw, m)
+	})
+}
+
+// 每次写入后追加Delimiter，与runtime.JSONPb一致
+func (j *JSONPb) NewEncoder(w io.Writer) runtime.Encoder {
+	return runtime.EncoderFunc(func(v interface{}) error {
+		ret, err := j.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(ret); err != nil {
+			return err
+		}
+		_, err = w.Write(j.Delimiter())
+		return err
+	})
+}
+
+// 流式输出的分隔符
+func (j *JSONPb) Delimiter() []byte {
+	return []byte("\n")
+}
+
+func (j *JSONPb) fallback() *runtime.JSONPb {
+	return &runtime.JSONPb{MarshalOptions: j.MarshalOptions, UnmarshalOptions: j.UnmarshalOptions}
+}
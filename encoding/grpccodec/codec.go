@@ -0,0 +1,50 @@
+// 基于生成代码的gRPC json编解码器，import后注册为"json"子类型
+//
+//	import _ "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/grpccodec"
+//	conn.Invoke(ctx, method, req, resp, grpc.CallContentSubtype("json"))
+package grpccodec
+
+import (
+	"fmt"
+
+	fastjsonpb "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/json"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// 注册的子类型，对应content-type application/grpc+json
+const Name = "json"
+
+func init() {
+	encoding.RegisterCodec(Codec{})
+}
+
+// 实现encoding.Codec，message实现了FastJsonpb时使用生成的方法，否则使用protojson
+// 两种方式的输出格式一致：与protojson相同，64位整数、NaN、Infinity输出为字符串，null作为未设置的字段
+// 与protobuf二进制编码一致，反序列化时忽略未知字段，便于服务端和客户端分别升级
+type Codec struct{}
+
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	if m, ok := v.(fastjsonpb.FastJsonpb); ok {
+		return fastjsonpb.MarshalOptions{QuoteInt64: true, ProtojsonFloat: true}.Marshal(m)
+	}
+	if m, ok := v.(proto.Message); ok {
+		return protojson.Marshal(m)
+	}
+	return nil, fmt.Errorf("grpccodec: %T is not a proto.Message", v)
+}
+
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	if m, ok := v.(fastjsonpb.FastJsonpb); ok {
+		return fastjsonpb.UnmarshalOptions{DiscardUnknown: true, NullAsUnset: true}.Unmarshal(data, m)
+	}
+	if m, ok := v.(proto.Message); ok {
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, m)
+	}
+	return fmt.Errorf("grpccodec: %T is not a proto.Message", v)
+}
+
+func (Codec) Name() string {
+	return Name
+}
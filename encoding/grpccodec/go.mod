@@ -0,0 +1,11 @@
+module github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/grpccodec
+
+go 1.17
+
+require (
+	github.com/superjsf2010/protoc-gen-fastjsonpb v1.0.0
+	google.golang.org/grpc v1.47.0
+	google.golang.org/protobuf v1.28.0
+)
+
+replace github.com/superjsf2010/protoc-gen-fastjsonpb => ../../
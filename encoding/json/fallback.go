@@ -0,0 +1,90 @@
+package json
+
+import (
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/jsonparser"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// 序列化message，没有生成代码（如第三方proto、dynamicpb）时通过反射处理
+// 有特殊JSON格式的Well-Known Types使用protojson，不支持NumberOrder、EscapeHTML、ASCIIOnly，64位整数总是输出为字符串
+// 生成代码中类型来自其他package的字段也通过该方法序列化
+func MarshalMessage(buf *buffer.Buffer, m proto.Message) {
+	if fm, ok := m.(FastJsonpb); ok {
+		fm.FastMarshal(buf)
+		return
+	}
+	if mr := m.ProtoReflect(); !wellKnownTypes[mr.Descriptor().FullName()] {
+		MarshalReflect(buf, mr)
+		return
+	}
+	ret, err := protojson.MarshalOptions{
+		UseProtoNames:   buf.UseProtoNames(),
+		UseEnumNumbers:  buf.UseEnumNumbers(),
+		EmitUnpopulated: buf.EmitUnpopulated(),
+	}.Marshal(m)
+	if err != nil {
+		buf.SetErr(err)
+		buf.WriteString("{}")
+		return
+	}
+	// protojson的输出会随机插入空白，去掉空白保证输出稳定
+	writeCompact(buf, ret)
+}
+
+// 去掉string以外的空白后写入buf，data为protojson输出的合法JSON
+func writeCompact(buf *buffer.Buffer, data []byte) {
+	start := 0
+	inStr := false
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; {
+		case inStr:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inStr = false
+			}
+		case c == '"':
+			inStr = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			buf.Write(data[start:i])
+			start = i + 1
+		}
+	}
+	buf.Write(data[start:])
+}
+
+// 反序列化message，没有生成代码时通过反射处理，错误通过panic报告
+// 生成代码中类型来自其他package的字段也通过该方法反序列化
+// Well-Known Types使用protojson，只支持DiscardUnknown、Merge，其他解析选项不生效
+func UnmarshalMessage(p *jsonparser.Parser, m proto.Message) {
+	if fm, ok := m.(FastJsonpb); ok {
+		if r, ok := m.(fastResetter); ok && !p.Merge() {
+			r.FastReset()
+		}
+		fm.FastUnmarshal(p)
+		return
+	}
+	if mr := m.ProtoReflect(); !wellKnownTypes[mr.Descriptor().FullName()] {
+		if !p.Merge() {
+			resetReflect(mr)
+		}
+		UnmarshalReflect(p, mr)
+		return
+	}
+	raw := p.RawValue()
+	opts := protojson.UnmarshalOptions{DiscardUnknown: p.DiscardUnknown()}
+	if !p.Merge() {
+		if err := opts.Unmarshal(raw, m); err != nil {
+			panic(err)
+		}
+		return
+	}
+	// protojson总是先清空message，合并模式下先解析到新的message
+	tmp := m.ProtoReflect().New().Interface()
+	if err := opts.Unmarshal(raw, tmp); err != nil {
+		panic(err)
+	}
+	proto.Merge(m, tmp)
+}
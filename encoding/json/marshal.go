@@ -1,18 +1,92 @@
 package json
 
 import (
+	"crypto/sha256"
 	"errors"
 
 	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/jsonparser"
+	"google.golang.org/protobuf/proto"
 )
 
+// 序列化选项
+type MarshalOptions struct {
+	// 为true时string包含非法UTF-8返回错误，默认替换为\ufffd
+	StrictUTF8 bool
+	// 为true时map按key排序输出，数字类型的key按数值排序，相同的message总是得到相同的结果
+	Deterministic bool
+	// 为true时按字段号输出字段（包括oneof成员），默认按声明顺序
+	NumberOrder bool
+	// 为true时输出RFC 8785（JCS）规范形式，可用于签名
+	// 所有对象的key按UTF-16编码单元排序，数字按ECMAScript的格式输出
+	// 与protojson一致，64位整数总是输出为字符串，不受QuoteInt64影响，避免超过2^53时丢失精度
+	// 浮点数的格式同ProtojsonFloat
+	Canonical bool
+	// 为true时转义<、>、&，与encoding/json的HTMLEscape一致，可直接嵌入HTML的<script>标签
+	EscapeHTML bool
+	// 为true时非ASCII字符转义为\uXXXX，BMP以外的字符使用代理对，输出只包含7位ASCII
+	ASCIIOnly bool
+	// 以下选项与protojson.MarshalOptions同名选项含义相同
+	// 为true时字段名使用proto中定义的名字，默认使用json名字（lowerCamelCase）
+	UseProtoNames bool
+	// 为true时enum输出数值，默认输出名字
+	UseEnumNumbers bool
+	// 为true时输出零值字段，未设置的message输出null，未设置的oneof不输出
+	// 输出中的null需要设置UnmarshalOptions.NullAsUnset才能反序列化
+	EmitUnpopulated bool
+	// 为true时int64、uint64等64位整数输出为字符串，与protojson一致，默认输出为数字
+	QuoteInt64 bool
+	// 为true时浮点数的格式与protojson一致：NaN、Infinity输出为字符串，绝对值小于1e-6或不小于1e21时使用指数形式
+	// 默认使用strconv的'f'格式，NaN、Infinity的输出不是合法的JSON
+	ProtojsonFloat bool
+}
+
 func Marshal(obj interface{}) ([]byte, error) {
-	fastjsonpbObj, ok := obj.(FastJsonpb)
+	return MarshalOptions{}.Marshal(obj)
+}
+
+func (o MarshalOptions) Marshal(obj interface{}) ([]byte, error) {
+	m, ok := obj.(proto.Message)
 	if !ok {
-		return nil, errors.New("object do not implements FastJsonpb")
+		return nil, errors.New("object do not implements proto.Message")
 	}
 	buf := buffer.New()
-	fastjsonpbObj.FastMarshal(buf)
-	buffer.BufPool.Put(buf)
-	return buf.Bytes(), nil
+	buf.SetStrictUTF8(o.StrictUTF8)
+	buf.SetDeterministic(o.Deterministic)
+	buf.SetNumberOrder(o.NumberOrder)
+	buf.SetEscapeHTML(o.EscapeHTML)
+	buf.SetEscapeASCII(o.ASCIIOnly)
+	buf.SetUseProtoNames(o.UseProtoNames)
+	buf.SetUseEnumNumbers(o.UseEnumNumbers)
+	buf.SetEmitUnpopulated(o.EmitUnpopulated)
+	buf.SetQuoteInt64(o.QuoteInt64 || o.Canonical)
+	buf.SetProtojsonFloat(o.ProtojsonFloat || o.Canonical)
+	MarshalMessage(buf, m)
+	if err := buf.Err(); err != nil {
+		buffer.Put(buf)
+		return nil, err
+	}
+	if o.Canonical {
+		cbuf := buffer.NewSize(buf.Len())
+		err := jsonparser.Canonicalize(cbuf, buf.Bytes())
+		buffer.Put(buf)
+		if err != nil {
+			buffer.Put(cbuf)
+			return nil, err
+		}
+		buf = cbuf
+	}
+	// buf放回Pool后会被复用，需要拷贝结果
+	ret := append([]byte(nil), buf.Bytes()...)
+	buffer.Put(buf)
+	return ret, nil
+}
+
+// 返回obj的JCS规范形式及其SHA-256摘要
+func CanonicalDigest(obj interface{}) ([]byte, [sha256.Size]byte, error) {
+	ret, err := MarshalOptions{Canonical: true}.Marshal(obj)
+	if err != nil {
+		return nil, [sha256.Size]byte{}, err
+	}
+	return ret, sha256.Sum256(ret), nil
 }
@@ -0,0 +1,440 @@
+package json
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/jsonparser"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// 有特殊JSON格式的Well-Known Types，交给protojson处理
+var wellKnownTypes = map[protoreflect.FullName]bool{
+	"google.protobuf.Any":         true,
+	"google.protobuf.Timestamp":   true,
+	"google.protobuf.Duration":    true,
+	"google.protobuf.FieldMask":   true,
+	"google.protobuf.Struct":      true,
+	"google.protobuf.Value":       true,
+	"google.protobuf.ListValue":   true,
+	"google.protobuf.DoubleValue": true,
+	"google.protobuf.FloatValue":  true,
+	"google.protobuf.Int64Value":  true,
+	"google.protobuf.UInt64Value": true,
+	"google.protobuf.Int32Value":  true,
+	"google.protobuf.UInt32Value": true,
+	"google.protobuf.BoolValue":   true,
+	"google.protobuf.StringValue": true,
+	"google.protobuf.BytesValue":  true,
+}
+
+type reflectField struct {
+	fd       protoreflect.FieldDescriptor
+	name     string
+	jsonName string
+	// 在seen中的序号，同一个oneof的成员共用一位
+	seen  int
+	oneof bool
+}
+
+// 由MessageDescriptor编译得到的字段表
+type reflectMessage struct {
+	// 声明顺序，oneof成员在最后，与生成代码的输出顺序一致
+	fields []*reflectField
+	// 按字段号排序
+	byNumber []*reflectField
+	// json名字和proto名字都可以查找到字段
+	byName map[string]*reflectField
+	nseen  int
+}
+
+// 缓存的字段表个数上限，超出时淘汰最久未使用的
+// 每次都重新构建descriptor（如按请求从FileDescriptorProto加载）时，避免缓存无限增长
+const maxReflectMessages = 1024
+
+// MessageDescriptor -> *reflectMessage，LRU
+type reflectCache struct {
+	mu  sync.Mutex
+	m   map[protoreflect.MessageDescriptor]*list.Element
+	lru list.List
+}
+
+type reflectCacheEntry struct {
+	md protoreflect.MessageDescriptor
+	rm *reflectMessage
+}
+
+var reflectMessages = reflectCache{m: make(map[protoreflect.MessageDescriptor]*list.Element)}
+
+func (c *reflectCache) get(md protoreflect.MessageDescriptor) *reflectMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.m[md]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*reflectCacheEntry).rm
+	}
+	return nil
+}
+
+// 并发编译同一个descriptor时返回先加入的字段表
+func (c *reflectCache) add(md protoreflect.MessageDescriptor, rm *reflectMessage) *reflectMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.m[md]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*reflectCacheEntry).rm
+	}
+	c.m[md] = c.lru.PushFront(&reflectCacheEntry{md: md, rm: rm})
+	if c.lru.Len() > maxReflectMessages {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.m, e.Value.(*reflectCacheEntry).md)
+	}
+	return rm
+}
+
+func reflectMessageOf(md protoreflect.MessageDescriptor) *reflectMessage {
+	if rm := reflectMessages.get(md); rm != nil {
+		return rm
+	}
+	fds := md.Fields()
+	rm := &reflectMessage{
+		fields: make([]*reflectField, 0, fds.Len()),
+		byName: make(map[string]*reflectField, 2*fds.Len()),
+	}
+	add := func(fd protoreflect.FieldDescriptor, seen int) {
+		f := &reflectField{
+			fd:       fd,
+			name:     string(fd.Name()),
+			jsonName: fd.JSONName(),
+			seen:     seen,
+			oneof:    fd.ContainingOneof() != nil,
+		}
+		rm.fields = append(rm.fields, f)
+		rm.byName[f.name] = f
+		rm.byName[f.jsonName] = f
+	}
+	for i := 0; i < fds.Len(); i++ {
+		if fd := fds.Get(i); fd.ContainingOneof() == nil {
+			add(fd, rm.nseen)
+			rm.nseen++
+		}
+	}
+	ods := md.Oneofs()
+	for i := 0; i < ods.Len(); i++ {
+		ofds := ods.Get(i).Fields()
+		for j := 0; j < ofds.Len(); j++ {
+			add(ofds.Get(j), rm.nseen)
+		}
+		rm.nseen++
+	}
+	rm.byNumber = append([]*reflectField(nil), rm.fields...)
+	sort.SliceStable(rm.byNumber, func(i, j int) bool { return rm.byNumber[i].fd.Number() < rm.byNumber[j].fd.Number() })
+	return reflectMessages.add(md, rm)
+}
+
+// 通过反射序列化message，用于dynamicpb等没有生成代码的message，输出与生成代码一致
+// 字段表按MessageDescriptor缓存（LRU），嵌套message仍通过MarshalMessage序列化
+func MarshalReflect(buf *buffer.Buffer, m protoreflect.Message) {
+	if !m.IsValid() {
+		buf.WriteString("{}")
+		return
+	}
+	rm := reflectMessageOf(m.Descriptor())
+	fields := rm.fields
+	if buf.NumberOrder() {
+		fields = rm.byNumber
+	}
+	buf.WriteString("{")
+	for _, f := range fields {
+		has := m.Has(f.fd)
+		// 未设置的oneof不输出
+		if !has && (f.oneof || !buf.EmitUnpopulated()) {
+			continue
+		}
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote(f.name)
+		} else {
+			buf.WriteStringWithQuote(f.jsonName)
+		}
+		buf.WriteString(":")
+		switch {
+		case f.fd.IsList():
+			marshalReflectList(buf, f.fd, m.Get(f.fd).List())
+		case f.fd.IsMap():
+			marshalReflectMap(buf, f.fd, m.Get(f.fd).Map())
+		case f.fd.Message() != nil && !has:
+			buf.WriteString("null")
+		default:
+			marshalReflectValue(buf, f.fd, m.Get(f.fd))
+		}
+		buf.WriteString(",")
+	}
+	buf.FixSymbol()
+	buf.WriteString("}")
+}
+
+func marshalReflectList(buf *buffer.Buffer, fd protoreflect.FieldDescriptor, l protoreflect.List) {
+	buf.WriteString("[")
+	for i, n := 0, l.Len(); i < n; i++ {
+		marshalReflectValue(buf, fd, l.Get(i))
+		buf.WriteString(",")
+	}
+	buf.FixSymbol()
+	buf.WriteString("]")
+}
+
+func marshalReflectMap(buf *buffer.Buffer, fd protoreflect.FieldDescriptor, m protoreflect.Map) {
+	buf.WriteString("{")
+	kfd, vfd := fd.MapKey(), fd.MapValue()
+	if buf.Deterministic() && m.Len() > 1 {
+		keys := make([]protoreflect.MapKey, 0, m.Len())
+		m.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		sortMapKeys(kfd.Kind(), keys)
+		for _, k := range keys {
+			marshalReflectEntry(buf, kfd, vfd, k, m.Get(k))
+		}
+	} else {
+		m.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			marshalReflectEntry(buf, kfd, vfd, k, v)
+			return true
+		})
+	}
+	buf.FixSymbol()
+	buf.WriteString("}")
+}
+
+// 与生成代码一致，数字类型的key按数值排序
+func sortMapKeys(kind protoreflect.Kind, keys []protoreflect.MapKey) {
+	switch kind {
+	case protoreflect.StringKind:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	case protoreflect.BoolKind:
+		sort.Slice(keys, func(i, j int) bool { return !keys[i].Bool() && keys[j].Bool() })
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Uint() < keys[j].Uint() })
+	default:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Int() < keys[j].Int() })
+	}
+}
+
+func marshalReflectEntry(buf *buffer.Buffer, kfd, vfd protoreflect.FieldDescriptor, k protoreflect.MapKey, v protoreflect.Value) {
+	switch kfd.Kind() {
+	case protoreflect.StringKind:
+		buf.WriteStringWithQuote(k.String())
+	case protoreflect.BoolKind:
+		buf.WriteByte('"')
+		buf.WriteBool(k.Bool())
+		buf.WriteByte('"')
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		buf.WriteByte('"')
+		buf.WriteUint64(k.Uint())
+		buf.WriteByte('"')
+	default:
+		buf.WriteByte('"')
+		buf.WriteInt64(k.Int())
+		buf.WriteByte('"')
+	}
+	buf.WriteString(":")
+	marshalReflectValue(buf, vfd, v)
+	buf.WriteString(",")
+}
+
+// 序列化非repeated的值，fd为字段或map value的描述
+func marshalReflectValue(buf *buffer.Buffer, fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		buf.WriteBool(v.Bool())
+	case protoreflect.StringKind:
+		buf.WriteStringWithQuote(v.String())
+	case protoreflect.BytesKind:
+		buf.WriteBytes(v.Bytes())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		buf.WriteInt32(int32(v.Int()))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		buf.WriteUint32(uint32(v.Uint()))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if buf.QuoteInt64() {
+			buf.WriteByte('"')
+			buf.WriteInt64(v.Int())
+			buf.WriteByte('"')
+		} else {
+			buf.WriteInt64(v.Int())
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if buf.QuoteInt64() {
+			buf.WriteByte('"')
+			buf.WriteUint64(v.Uint())
+			buf.WriteByte('"')
+		} else {
+			buf.WriteUint64(v.Uint())
+		}
+	case protoreflect.FloatKind:
+		buf.WriteFloat32(float32(v.Float()))
+	case protoreflect.DoubleKind:
+		buf.WriteFloat64(v.Float())
+	case protoreflect.EnumKind:
+		n := v.Enum()
+		if ev := fd.Enum().Values().ByNumber(n); ev != nil && !buf.UseEnumNumbers() {
+			buf.WriteStringWithQuote(string(ev.Name()))
+		} else {
+			// 与protojson一致，未定义的值输出为数字
+			buf.WriteInt32(int32(n))
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		MarshalMessage(buf, v.Message().Interface())
+	}
+}
+
+// 通过反射反序列化message，错误通过panic报告
+// 与生成的FastUnmarshal一样不会清空message，嵌套message通过UnmarshalMessage反序列化
+func UnmarshalReflect(p *jsonparser.Parser, m protoreflect.Message) {
+	rm := reflectMessageOf(m.Descriptor())
+	var arr [2]uint64
+	seen := arr[:]
+	if n := (rm.nseen + 63) / 64; n > len(arr) {
+		seen = make([]uint64, n)
+	}
+	p.Symbol('{')
+	for !p.IsSymbol('}') {
+		key := p.Key()
+		p.AssertSymbol(':')
+		f := rm.byName[key]
+		switch {
+		case f == nil:
+			p.Unknown(key)
+		case p.SkipDuplicate(seen, f.seen, key), p.SkipNull():
+		case f.fd.IsList():
+			unmarshalReflectList(p, m, f.fd)
+		case f.fd.IsMap():
+			unmarshalReflectMap(p, m, f.fd)
+		case f.fd.Message() != nil:
+			UnmarshalMessage(p, m.Mutable(f.fd).Message().Interface())
+		default:
+			m.Set(f.fd, unmarshalReflectScalar(p, f.fd))
+		}
+		p.AssertSymbol(',')
+	}
+	p.AssertSymbol(',')
+	p.Symbol('}')
+}
+
+func unmarshalReflectList(p *jsonparser.Parser, m protoreflect.Message, fd protoreflect.FieldDescriptor) {
+	p.Symbol('[')
+	l := m.Mutable(fd).List()
+	if !p.Merge() {
+		l.Truncate(0)
+	}
+	for !p.IsSymbol(']') {
+		if fd.Message() != nil {
+			v := l.NewElement()
+			UnmarshalMessage(p, v.Message().Interface())
+			l.Append(v)
+		} else {
+			l.Append(unmarshalReflectScalar(p, fd))
+		}
+		p.AssertSymbol(',')
+	}
+	p.AssertSymbol(',')
+	p.Symbol(']')
+}
+
+func unmarshalReflectMap(p *jsonparser.Parser, m protoreflect.Message, fd protoreflect.FieldDescriptor) {
+	p.Symbol('{')
+	if !p.Merge() {
+		m.Clear(fd)
+	}
+	mp := m.Mutable(fd).Map()
+	kfd, vfd := fd.MapKey(), fd.MapValue()
+	// 当前对象中已经出现的key，合并模式下mp中原有的key不算重复
+	var keys map[interface{}]struct{}
+	for !p.IsSymbol('}') {
+		var k protoreflect.MapKey
+		switch kfd.Kind() {
+		case protoreflect.StringKind:
+			k = protoreflect.ValueOfString(p.Str()).MapKey()
+		case protoreflect.BoolKind:
+			k = protoreflect.ValueOfBool(p.BolKey()).MapKey()
+		default:
+			k = unmarshalReflectScalar(p, kfd).MapKey()
+		}
+		p.AssertSymbol(':')
+		if p.CheckDuplicate() {
+			if _, ok := keys[k.Interface()]; ok {
+				raw := p.Raw()
+				if kfd.Kind() == protoreflect.StringKind {
+					raw = k.String()
+				}
+				if p.SkipDuplicateKey(raw) {
+					p.AssertSymbol(',')
+					continue
+				}
+			}
+			if keys == nil {
+				keys = make(map[interface{}]struct{})
+			}
+			keys[k.Interface()] = struct{}{}
+		}
+		if vfd.Message() != nil {
+			v := mp.NewValue()
+			UnmarshalMessage(p, v.Message().Interface())
+			mp.Set(k, v)
+		} else {
+			mp.Set(k, unmarshalReflectScalar(p, vfd))
+		}
+		p.AssertSymbol(',')
+	}
+	p.AssertSymbol(',')
+	p.Symbol('}')
+}
+
+func unmarshalReflectScalar(p *jsonparser.Parser, fd protoreflect.FieldDescriptor) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(p.Bol())
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(p.Str())
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(p.Bytes())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(p.Int32())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(p.Uint32())
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(p.Int64())
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(p.Uint64())
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(p.Float32())
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(p.Float64())
+	case protoreflect.EnumKind:
+		t, s, i := p.Enum()
+		if t == jsonparser.EnumNumber {
+			// 与protojson一致，数字不检查是否定义
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(i))
+		}
+		ev := fd.Enum().Values().ByName(protoreflect.Name(s))
+		if ev == nil {
+			panic("enum " + string(fd.Enum().FullName()) + " value do not match")
+		}
+		return protoreflect.ValueOfEnum(ev.Number())
+	}
+	panic("unsupported field kind " + fd.Kind().String())
+}
+
+// 清空message，与生成的FastReset一致
+func resetReflect(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		m.Clear(fd)
+		return true
+	})
+	if len(m.GetUnknown()) > 0 {
+		m.SetUnknown(nil)
+	}
+}
@@ -0,0 +1,96 @@
+package json
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+type registeredType struct {
+	new     func() proto.Message
+	release func(proto.Message)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]registeredType{}
+)
+
+// 生成代码在init中调用，按message的完整名字（如example.Example）注册
+// newFn从Pool获取message，release清空message后放回Pool
+// 同一个proto被编译到多个package时名字会重复，与protobuf的冲突处理一致，输出警告并保留第一次注册的类型
+func RegisterType(name string, newFn func() proto.Message, release func(proto.Message)) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		fmt.Fprintf(os.Stderr, "WARNING: fastjsonpb: message %s is already registered, the new registration is ignored\n", name)
+		return
+	}
+	registry[name] = registeredType{new: newFn, release: release}
+}
+
+func findType(name string) (registeredType, bool) {
+	registryMu.RLock()
+	t, ok := registry[name]
+	registryMu.RUnlock()
+	return t, ok
+}
+
+// 去掉Any中type URL的前缀，如type.googleapis.com/example.Example
+func typeName(name string) string {
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// NewByName、UnmarshalByName返回的message不需要放回Pool时使用
+func noRelease() {}
+
+// 按完整名字或Any的type URL创建message
+// 注册过的类型从Pool获取，其他类型（如Well-Known Types）从protoregistry.GlobalTypes查找
+// 调用release将message放回Pool，之后不能再使用message；多次调用release只生效一次，不调用也可以
+func NewByName(name string) (m proto.Message, release func(), err error) {
+	name = typeName(name)
+	if t, ok := findType(name); ok {
+		m = t.new()
+		released := false
+		return m, func() {
+			if !released {
+				released = true
+				t.release(m)
+			}
+		}, nil
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, nil, fmt.Errorf("fastjsonpb: unknown message type %q", name)
+	}
+	return mt.New().Interface(), noRelease, nil
+}
+
+// 按完整名字或Any的type URL反序列化，与Unmarshal一样遇到未知字段返回错误，release同NewByName
+func UnmarshalByName(name string, data []byte) (m proto.Message, release func(), err error) {
+	return UnmarshalOptions{}.UnmarshalByName(name, data)
+}
+
+// 使用Arena时嵌套message从Arena分配，返回的release不做处理
+func (o UnmarshalOptions) UnmarshalByName(name string, data []byte) (m proto.Message, release func(), err error) {
+	m, release, err = NewByName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.Arena != nil {
+		release = noRelease
+	}
+	if err := o.Unmarshal(data, m); err != nil {
+		release()
+		return nil, nil, err
+	}
+	return m, release, nil
+}
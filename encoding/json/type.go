@@ -9,3 +9,8 @@ type FastJsonpb interface {
 	FastMarshal(buf *buffer.Buffer)
 	FastUnmarshal(p *jsonparser.Parser)
 }
+
+// 清空message，旧版本生成的代码没有该方法
+type fastResetter interface {
+	FastReset()
+}
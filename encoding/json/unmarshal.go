@@ -2,16 +2,104 @@ package json
 
 import (
 	"errors"
+	"fmt"
+	"sync"
 
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/arena"
 	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/jsonparser"
+	"google.golang.org/protobuf/proto"
 )
 
+var parserPool = sync.Pool{
+	New: func() interface{} {
+		return jsonparser.New(nil)
+	},
+}
+
+// 反序列化选项
+type UnmarshalOptions struct {
+	// 默认会拷贝string、bytes数据，解析结果不受输入数据后续修改的影响
+	// 为true时直接引用输入数据，调用方需保证输入数据的生命周期不短于message
+	ZeroCopy bool
+	// 不为nil时嵌套message、数组、string数据从Arena分配，通过Arena.Free统一释放
+	// 此时不能对解析得到的message（包括顶层message）调用Destructor
+	Arena *arena.Arena
+	// 最大嵌套深度，0表示使用jsonparser.DefaultMaxDepth，小于0表示不限制
+	MaxDepth int
+	// 输入数据的最大字节数，0表示不限制
+	MaxSize int
+	// string的最大字节数，0表示不限制
+	MaxStringLen int
+	// repeated字段、map以及未知字段中数组、对象的最大元素个数，0表示不限制
+	MaxElements int
+	// 重复字段的处理方式，默认保留最后一个
+	// DuplicateError时同一个oneof出现多个成员也会返回错误
+	Duplicate jsonparser.DuplicatePolicy
+	// 为true时string包含非法UTF-8返回错误，默认原样保留
+	StrictUTF8 bool
+	// 与protojson一致，默认遇到未知字段返回错误（包含字段路径）
+	// 为true时忽略未知字段，以preserve_unknown=true生成的message保留未知字段
+	DiscardUnknown bool
+	// 设置DiscardUnknown时遇到未知字段调用，可用于统计客户端仍在使用的废弃或拼写错误的字段
+	// path为未知字段所在对象的路径，raw为value的原始数据，只在回调期间有效
+	OnUnknownField func(path, key string, raw []byte)
+	// 默认与protojson一致，反序列化前先清空message
+	// 为true时按proto.Merge的规则合并到已有数据：非repeated字段覆盖，repeated字段追加，map按key合并
+	Merge bool
+	// 为true时与protojson一致，字段的值为null时作为未设置处理，默认返回错误
+	NullAsUnset bool
+}
+
+// 与零值的UnmarshalOptions、protojson.Unmarshal一致，遇到未知字段返回错误
 func Unmarshal(data []byte, obj interface{}) error {
-	fastjsonpbObj, ok := obj.(FastJsonpb)
+	return UnmarshalOptions{}.Unmarshal(data, obj)
+}
+
+func (o UnmarshalOptions) Unmarshal(data []byte, obj interface{}) (err error) {
+	m, ok := obj.(proto.Message)
 	if !ok {
-		return errors.New("object do not implements FastJsonpb")
+		return errors.New("object do not implements proto.Message")
 	}
-	p := jsonparser.New(data)
-	fastjsonpbObj.FastUnmarshal(p)
+	p := parserPool.Get().(*jsonparser.Parser)
+	p.Reset(data)
+	p.SetOptions(jsonparser.Options{
+		ZeroCopy: o.ZeroCopy,
+		Arena:    o.Arena,
+
+		MaxDepth:     o.MaxDepth,
+		MaxSize:      o.MaxSize,
+		MaxStringLen: o.MaxStringLen,
+		MaxElements:  o.MaxElements,
+		Duplicate:    o.Duplicate,
+		StrictUTF8:   o.StrictUTF8,
+
+		RejectUnknown:  !o.DiscardUnknown,
+		OnUnknownField: o.OnUnknownField,
+		Merge:          o.Merge,
+		NullAsUnset:    o.NullAsUnset,
+	})
+	defer func() {
+		// 解析器通过panic报告语法错误，这里转换成error返回
+		if r := recover(); r != nil {
+			err = toError(r)
+		}
+		// 释放对输入数据的引用后再放回Pool
+		p.Reset(nil)
+		parserPool.Put(p)
+	}()
+	UnmarshalMessage(p, m)
+	// 顶层对象之后不允许出现其他数据
+	p.End()
 	return nil
 }
+
+func toError(r interface{}) error {
+	switch v := r.(type) {
+	case error:
+		return v
+	case string:
+		return errors.New(v)
+	default:
+		return fmt.Errorf("%v", v)
+	}
+}
@@ -0,0 +1,45 @@
+// 旧版github.com/golang/protobuf/jsonpb的适配，单独成包，只有使用jsonpb_methods=true的代码才依赖旧版protobuf
+package jsonpbcompat
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+
+	"github.com/golang/protobuf/jsonpb"
+	fastjsonpb "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/json"
+)
+
+// 以jsonpb_methods=true编译时生成的MarshalJSONPB调用该方法，将jsonpb.Marshaler的选项映射到快速路径
+// 与jsonpb一致，64位整数输出为字符串，NaN、Infinity输出为字符串；不支持AnyResolver
+func MarshalJSONPB(m *jsonpb.Marshaler, obj fastjsonpb.FastJsonpb) ([]byte, error) {
+	if m == nil {
+		m = &jsonpb.Marshaler{}
+	}
+	ret, err := fastjsonpb.MarshalOptions{
+		UseProtoNames:   m.OrigName,
+		UseEnumNumbers:  m.EnumsAsInts,
+		EmitUnpopulated: m.EmitDefaults,
+		QuoteInt64:      true,
+		ProtojsonFloat:  true,
+	}.Marshal(obj)
+	if err != nil || m.Indent == "" {
+		return ret, err
+	}
+	var out bytes.Buffer
+	if err := stdjson.Indent(&out, ret, "", m.Indent); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// 以jsonpb_methods=true编译时生成的UnmarshalJSONPB调用该方法
+// 与jsonpb一致，合并到已有数据，字段的值为null时作为未设置处理，顶层的null不做处理
+func UnmarshalJSONPB(u *jsonpb.Unmarshaler, data []byte, obj fastjsonpb.FastJsonpb) error {
+	if u == nil {
+		u = &jsonpb.Unmarshaler{}
+	}
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	return fastjsonpb.UnmarshalOptions{DiscardUnknown: u.AllowUnknownFields, Merge: true, NullAsUnset: true}.Unmarshal(data, obj)
+}
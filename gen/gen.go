@@ -1,6 +1,8 @@
 package gen
 
 import (
+	"flag"
+	"sort"
 	"strconv"
 
 	"google.golang.org/protobuf/compiler/protogen"
@@ -8,36 +10,53 @@ import (
 	"google.golang.org/protobuf/types/pluginpb"
 )
 
+// 生成代码中引用的encoding/json
+const fastjsonpbPkg = protogen.GoImportPath("github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/json")
+
+// 旧版jsonpb的适配放在单独的package中，encoding/json不依赖github.com/golang/protobuf
+const jsonpbcompatPkg = protogen.GoImportPath("github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/jsonpbcompat")
+
 type FastJsonpbGen struct {
-	plugin      *protogen.Plugin
-	messagesMap map[string]*protogen.Message
-	enumsMap    map[string]*protogen.Enum
+	plugin *protogen.Plugin
+	// 保留未知字段，序列化时原样写回
+	preserveUnknown bool
+	// 生成MarshalJSON、UnmarshalJSON方法
+	jsonMethods bool
+	// 生成github.com/golang/protobuf/jsonpb的MarshalJSONPB、UnmarshalJSONPB方法
+	jsonpbMethods bool
 }
 
 func New(req *pluginpb.CodeGeneratorRequest) (*FastJsonpbGen, error) {
-	opts := protogen.Options{}
+	gen := &FastJsonpbGen{}
+	// 插件参数，如--fastjsonpb_out=preserve_unknown=true:.
+	var flags flag.FlagSet
+	flags.BoolVar(&gen.preserveUnknown, "preserve_unknown", false, "preserve unknown fields")
+	flags.BoolVar(&gen.jsonMethods, "json_methods", false, "generate MarshalJSON and UnmarshalJSON")
+	flags.BoolVar(&gen.jsonpbMethods, "jsonpb_methods", false, "generate MarshalJSONPB and UnmarshalJSONPB")
+	opts := protogen.Options{
+		ParamFunc: flags.Set,
+	}
 	plugin, err := opts.New(req)
 	if err != nil {
 		return nil, err
 	}
-	gen := &FastJsonpbGen{
-		plugin:      plugin,
-		messagesMap: make(map[string]*protogen.Message),
-		enumsMap:    make(map[string]*protogen.Enum),
-	}
+	gen.plugin = plugin
 	return gen, nil
 }
 
 func (g *FastJsonpbGen) GenerateAllFiles() (*pluginpb.CodeGeneratorResponse, error) {
 	for _, protoFile := range g.plugin.Files {
+		// import的文件（如Well-Known Types）不生成
+		if !protoFile.Generate {
+			continue
+		}
 		filename := protoFile.GeneratedFilenamePrefix + ".pb.fastjsonpb.go"
-		gf := g.plugin.NewGeneratedFile(filename, ".")
+		gf := g.plugin.NewGeneratedFile(filename, protoFile.GoImportPath)
 		g.generateComments(protoFile, gf)
 		g.generatePackageName(protoFile, gf)
 		g.generateImport(protoFile, gf)
-		g.buildMessageIndex(protoFile, gf)
-		g.buildEnumIndex(protoFile, gf)
 		g.generateMessage(protoFile, gf)
+		g.generateRegister(protoFile, gf)
 	}
 	return g.plugin.Response(), nil
 }
@@ -56,6 +75,7 @@ func (g *FastJsonpbGen) generatePackageName(protoFile *protogen.File, gf *protog
 
 // 生成依赖包
 func (g *FastJsonpbGen) generateImport(protoFile *protogen.File, gf *protogen.GeneratedFile) {
+	g.genImport("", "github.com/superjsf2010/protoc-gen-fastjsonpb/x/arena", gf)
 	g.genImport("", "github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer", gf)
 	g.genImport("", "github.com/superjsf2010/protoc-gen-fastjsonpb/x/jsonparser", gf)
 	g.genImport("", "sync", gf)
@@ -68,53 +88,6 @@ func (g *FastJsonpbGen) genImport(name string, importPath string, gf *protogen.G
 	})
 }
 
-func (g *FastJsonpbGen) buildMessageIndex(protoFile *protogen.File, gf *protogen.GeneratedFile) {
-	for _, message := range protoFile.Messages {
-		// 顶层message
-		g.addMessageType(message)
-	}
-}
-
-func (g *FastJsonpbGen) addMessageType(message *protogen.Message) {
-	fullName := string(message.Desc.FullName())
-	if _, ok := g.messagesMap[fullName]; !ok {
-		g.messagesMap[fullName] = message
-	}
-	// 嵌套message
-	for _, m := range message.Messages {
-		g.addMessageType(m)
-	}
-}
-
-func (g *FastJsonpbGen) buildEnumIndex(protoFile *protogen.File, gf *protogen.GeneratedFile) {
-	// 顶层enums
-	for _, e := range protoFile.Enums {
-		g.addTopEnumType(e)
-	}
-	// 顶层message
-	for _, message := range protoFile.Messages {
-		// 嵌套enum
-		g.addNestedEnumType(message)
-	}
-}
-
-func (g *FastJsonpbGen) addTopEnumType(e *protogen.Enum) {
-	fullName := string(e.Desc.FullName())
-	if _, ok := g.enumsMap[fullName]; !ok {
-		g.enumsMap[fullName] = e
-	}
-}
-
-func (g *FastJsonpbGen) addNestedEnumType(message *protogen.Message) {
-	for _, e := range message.Enums {
-		g.addTopEnumType(e)
-	}
-	// 嵌套message
-	for _, m := range message.Messages {
-		g.addNestedEnumType(m)
-	}
-}
-
 // 生成message对应信息
 func (g *FastJsonpbGen) generateMessage(protoFile *protogen.File, gf *protogen.GeneratedFile) {
 	// 处理顶层message
@@ -135,8 +108,15 @@ func (g *FastJsonpbGen) genMessage(message *protogen.Message, gf *protogen.Gener
 	g.generateMarshal(message, gf)
 	g.generateUnmarshal(message, gf)
 	g.generatePool(message, gf)
+	g.generateArena(message, gf)
 	g.generateDestructor(message, gf)
 	g.generateEmpty(message, gf)
+	if g.jsonMethods {
+		g.generateJSONMethods(message, gf)
+	}
+	if g.jsonpbMethods {
+		g.generateJSONPBMethods(message, gf)
+	}
 	// 处理内嵌message
 	for _, m := range message.Messages {
 		if m.Desc.IsMapEntry() {
@@ -153,21 +133,23 @@ func (g *FastJsonpbGen) genMessage(message *protogen.Message, gf *protogen.Gener
 
 // 生成序列化方法
 func (g *FastJsonpbGen) generateMarshal(message *protogen.Message, gf *protogen.GeneratedFile) {
+	byNumber := g.numberOrderFields(message)
 	gf.P(`func (x *` + message.GoIdent.GoName + `) FastMarshal(buf *buffer.Buffer) {`)
 	gf.P(`if x == nil {`)
 	gf.P(`buf.WriteString("{}")`)
+	gf.P(`return`)
 	gf.P(`}`)
+	if byNumber != nil {
+		gf.P(`if buf.NumberOrder() {`)
+		gf.P(`x.fastMarshalNumberOrder(buf)`)
+		gf.P(`return`)
+		gf.P(`}`)
+	}
 	g.symbolMarshal(gf, `{`)
 	// 处理simple字段
 	for _, f := range message.Fields {
 		if f.Desc.ContainingOneof() == nil {
-			if f.Desc.IsList() {
-				g.listMarshal(gf, f)
-			} else if f.Desc.IsMap() {
-				g.mapMarshal(gf, f)
-			} else {
-				g.typeMarshal(gf, f)
-			}
+			g.fieldMarshal(gf, f)
 			gf.P(``)
 		}
 	}
@@ -191,6 +173,58 @@ func (g *FastJsonpbGen) generateMarshal(message *protogen.Message, gf *protogen.
 		gf.P(`}`)
 		gf.P(``)
 	}
+	g.endMarshal(message, gf)
+
+	if byNumber == nil {
+		return
+	}
+	// 按字段号输出，oneof成员按各自的字段号穿插在其他字段之间
+	gf.P(`func (x *` + message.GoIdent.GoName + `) fastMarshalNumberOrder(buf *buffer.Buffer) {`)
+	g.symbolMarshal(gf, `{`)
+	for _, f := range byNumber {
+		if of := f.Oneof; of != nil {
+			g.oneofTypeMarshal(gf, f, `if _, ok := x.Get`+of.GoName+`().`)
+			gf.P(`}`)
+		} else {
+			g.fieldMarshal(gf, f)
+		}
+		gf.P(``)
+	}
+	g.endMarshal(message, gf)
+}
+
+// 按字段号排序的字段，与声明顺序（oneof成员在最后）相同时返回nil
+func (g *FastJsonpbGen) numberOrderFields(message *protogen.Message) []*protogen.Field {
+	fields := make([]*protogen.Field, 0, len(message.Fields))
+	for _, f := range message.Fields {
+		if f.Desc.ContainingOneof() == nil {
+			fields = append(fields, f)
+		}
+	}
+	for _, of := range message.Oneofs {
+		fields = append(fields, of.Fields...)
+	}
+	if sort.SliceIsSorted(fields, func(i, j int) bool { return fields[i].Desc.Number() < fields[j].Desc.Number() }) {
+		return nil
+	}
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Desc.Number() < fields[j].Desc.Number() })
+	return fields
+}
+
+func (g *FastJsonpbGen) fieldMarshal(gf *protogen.GeneratedFile, f *protogen.Field) {
+	if f.Desc.IsList() {
+		g.listMarshal(gf, f)
+	} else if f.Desc.IsMap() {
+		g.mapMarshal(gf, f)
+	} else {
+		g.typeMarshal(gf, f)
+	}
+}
+
+func (g *FastJsonpbGen) endMarshal(message *protogen.Message, gf *protogen.GeneratedFile) {
+	if g.preserveUnknown {
+		gf.P(g.unknownTable(message) + `.Marshal(buf, x.unknownFields)`)
+	}
 	// 处理多余的逗号
 	g.fixSymbolMarshal(gf)
 	g.symbolMarshal(gf, `}`)
@@ -200,12 +234,12 @@ func (g *FastJsonpbGen) generateMarshal(message *protogen.Message, gf *protogen.
 
 // 处理array
 func (g *FastJsonpbGen) listMarshal(gf *protogen.GeneratedFile, f *protogen.Field) {
-	gf.P(`if !x.IsEmpty` + f.GoName + `() {`)
-	g.keyMarshal(gf, protoreflect.StringKind, `"`+f.Desc.JSONName()+`"`)
+	gf.P(`if !x.IsEmpty` + f.GoName + `() || buf.EmitUnpopulated() {`)
+	g.nameMarshal(gf, f)
 	g.symbolMarshal(gf, `[`)
 	gf.P(`for i,_ := range x.` + f.GoName + `{`)
 	// 为提高性能使用下标形式访问
-	g.valMarshal(gf, f.Desc.Kind(), `x.`+f.GoName+`[i]`)
+	g.valMarshal(gf, f, `x.`+f.GoName+`[i]`)
 	g.symbolMarshal(gf, `,`)
 	gf.P(`}`)
 	g.fixSymbolMarshal(gf)
@@ -218,21 +252,28 @@ func (g *FastJsonpbGen) listMarshal(gf *protogen.GeneratedFile, f *protogen.Fiel
 func (g *FastJsonpbGen) mapMarshal(gf *protogen.GeneratedFile, f *protogen.Field) {
 	// protobuf官方文档说明map的key_type
 	// where the key_type can be any integral or string type (so, any scalar type except for floating point types and bytes). Note that enum is not a valid key_type
-	gf.P(`if !x.IsEmpty` + f.GoName + `() {`)
-	g.keyMarshal(gf, protoreflect.StringKind, `"`+f.Desc.JSONName()+`"`)
-	key := f.Desc.MapKey()
-	val := f.Desc.MapValue()
+	gf.P(`if !x.IsEmpty` + f.GoName + `() || buf.EmitUnpopulated() {`)
+	g.nameMarshal(gf, f)
 	g.symbolMarshal(gf, `{`)
-	gf.P(`for k,_ := range x.` + f.GoName + `{`)
-	// 为了让field和map_key使用同一个keyMarshal方法，这里加个特殊判断
-	if key.Kind() == protoreflect.StringKind {
-		g.keyMarshal(gf, key.Kind(), `k`)
+	// Deterministic时按key排序，数字、bool类型按值排序
+	gf.P(`if buf.Deterministic() {`)
+	gf.P(`keys := make([]` + g.mapKeyTypeName(f) + `, 0, len(x.` + f.GoName + `))`)
+	gf.P(`for k := range x.` + f.GoName + ` {`)
+	gf.P(`keys = append(keys, k)`)
+	gf.P(`}`)
+	sortSlice := gf.QualifiedGoIdent(protogen.GoIdent{GoName: "Slice", GoImportPath: "sort"})
+	if f.Desc.MapKey().Kind() == protoreflect.BoolKind {
+		gf.P(sortSlice + `(keys, func(i, j int) bool { return !keys[i] && keys[j] })`)
 	} else {
-		g.keyMarshal(gf, key.Kind(), `k`)
+		gf.P(sortSlice + `(keys, func(i, j int) bool { return keys[i] < keys[j] })`)
 	}
-	// 为提高性能使用下标形式访问
-	g.valMarshal(gf, val.Kind(), `x.`+f.GoName+`[k]`)
-	g.symbolMarshal(gf, `,`)
+	gf.P(`for _, k := range keys {`)
+	g.mapEntryMarshal(gf, f)
+	gf.P(`}`)
+	gf.P(`} else {`)
+	gf.P(`for k,_ := range x.` + f.GoName + `{`)
+	g.mapEntryMarshal(gf, f)
+	gf.P(`}`)
 	gf.P(`}`)
 	g.fixSymbolMarshal(gf)
 	g.symbolMarshal(gf, `}`)
@@ -240,37 +281,61 @@ func (g *FastJsonpbGen) mapMarshal(gf *protogen.GeneratedFile, f *protogen.Field
 	gf.P(`}`)
 }
 
+func (g *FastJsonpbGen) mapEntryMarshal(gf *protogen.GeneratedFile, f *protogen.Field) {
+	g.keyMarshal(gf, f.Desc.MapKey().Kind(), `k`)
+	// 为提高性能使用下标形式访问
+	g.valMarshal(gf, f.Message.Fields[1], `x.`+f.GoName+`[k]`)
+	g.symbolMarshal(gf, `,`)
+}
+
 // 处理一般类型
 func (g *FastJsonpbGen) typeMarshal(gf *protogen.GeneratedFile, f *protogen.Field) {
-	gf.P(`if !x.IsEmpty` + f.GoName + `() {`)
-	g.keyMarshal(gf, protoreflect.StringKind, `"`+f.Desc.JSONName()+`"`)
-	g.valMarshal(gf, f.Desc.Kind(), `x.Get`+f.GoName+`()`)
+	if f.Desc.Kind() != protoreflect.MessageKind {
+		gf.P(`if !x.IsEmpty` + f.GoName + `() || buf.EmitUnpopulated() {`)
+	} else {
+		gf.P(`if !x.IsEmpty` + f.GoName + `() {`)
+	}
+	g.nameMarshal(gf, f)
+	g.valMarshal(gf, f, `x.Get`+f.GoName+`()`)
 	g.symbolMarshal(gf, `,`)
+	if f.Desc.Kind() == protoreflect.MessageKind {
+		// 与protojson一致，未设置的message输出null
+		gf.P(`} else if buf.EmitUnpopulated() {`)
+		g.nameMarshal(gf, f)
+		gf.P(`buf.WriteString("null")`)
+		g.symbolMarshal(gf, `,`)
+	}
 	gf.P(`}`)
 }
 
 // 处理oneof一般类型
 func (g *FastJsonpbGen) oneofTypeMarshal(gf *protogen.GeneratedFile, f *protogen.Field, prefix string) {
 	gf.P(prefix + `(*` + f.GoIdent.GoName + `); ok {`)
-	g.keyMarshal(gf, protoreflect.StringKind, `"`+f.Desc.JSONName()+`"`)
-	g.valMarshal(gf, f.Desc.Kind(), `x.Get`+f.GoName+`()`)
+	g.nameMarshal(gf, f)
+	g.valMarshal(gf, f, `x.Get`+f.GoName+`()`)
 	g.symbolMarshal(gf, `,`)
 }
 
-func (g *FastJsonpbGen) valMarshal(gf *protogen.GeneratedFile, k protoreflect.Kind, v string) {
-	switch k {
+func (g *FastJsonpbGen) valMarshal(gf *protogen.GeneratedFile, f *protogen.Field, v string) {
+	switch f.Desc.Kind() {
 	case protoreflect.BoolKind:
 		gf.P(`buf.WriteBool(` + v + `)`)
 	case protoreflect.EnumKind:
-		gf.P(`buf.WriteStringWithQuote(` + v + `.String())`)
+		names := gf.QualifiedGoIdent(protogen.GoIdent{GoName: f.Enum.GoIdent.GoName + "_name", GoImportPath: f.Enum.GoIdent.GoImportPath})
+		gf.P(`if s, ok := ` + names + `[int32(` + v + `)]; ok && !buf.UseEnumNumbers() {`)
+		gf.P(`buf.WriteStringWithQuote(s)`)
+		gf.P(`} else {`)
+		// 与protojson一致，未定义的值输出为数字
+		gf.P(`buf.WriteInt32(int32(` + v + `))`)
+		gf.P(`}`)
 	case protoreflect.Int32Kind, protoreflect.Sint32Kind:
 		gf.P(`buf.WriteInt32(` + v + `)`)
 	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
 		gf.P(`buf.WriteUint32(` + v + `)`)
 	case protoreflect.Int64Kind, protoreflect.Sint64Kind:
-		gf.P(`buf.WriteInt64(` + v + `)`)
+		g.int64Marshal(gf, `buf.WriteInt64(`+v+`)`)
 	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-		gf.P(`buf.WriteUint64(` + v + `)`)
+		g.int64Marshal(gf, `buf.WriteUint64(`+v+`)`)
 	case protoreflect.Sfixed32Kind, protoreflect.FloatKind:
 		gf.P(`buf.WriteFloat32(` + v + `)`)
 	case protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
@@ -280,7 +345,11 @@ func (g *FastJsonpbGen) valMarshal(gf *protogen.GeneratedFile, k protoreflect.Ki
 	case protoreflect.BytesKind:
 		gf.P(`buf.WriteBytes(` + v + `)`)
 	case protoreflect.MessageKind:
-		gf.P(v + `.FastMarshal(buf)`)
+		if g.foreignMessage(f) {
+			gf.P(fastjsonpbPkg.Ident("MarshalMessage"), `(buf, `+v+`)`)
+		} else {
+			gf.P(v + `.FastMarshal(buf)`)
+		}
 	case protoreflect.GroupKind:
 		// TODO  unspported type
 	default:
@@ -288,22 +357,54 @@ func (g *FastJsonpbGen) valMarshal(gf *protogen.GeneratedFile, k protoreflect.Ki
 	}
 }
 
-// 写入key
+// 64位整数QuoteInt64时加引号
+func (g *FastJsonpbGen) int64Marshal(gf *protogen.GeneratedFile, write string) {
+	gf.P(`if buf.QuoteInt64() {`)
+	gf.P(`buf.WriteByte('"')`)
+	gf.P(write)
+	gf.P(`buf.WriteByte('"')`)
+	gf.P(`} else {`)
+	gf.P(write)
+	gf.P(`}`)
+}
+
+// 写入字段名，proto名字与json名字不同时根据UseProtoNames选择
+func (g *FastJsonpbGen) nameMarshal(gf *protogen.GeneratedFile, f *protogen.Field) {
+	if string(f.Desc.Name()) == f.Desc.JSONName() {
+		g.keyMarshal(gf, protoreflect.StringKind, `"`+f.Desc.JSONName()+`"`)
+		return
+	}
+	gf.P(`if buf.UseProtoNames() {`)
+	g.keyMarshal(gf, protoreflect.StringKind, `"`+string(f.Desc.Name())+`"`)
+	gf.P(`} else {`)
+	g.keyMarshal(gf, protoreflect.StringKind, `"`+f.Desc.JSONName()+`"`)
+	gf.P(`}`)
+}
+
+// 写入key，map中非string类型的key也需要加引号
 func (g *FastJsonpbGen) keyMarshal(gf *protogen.GeneratedFile, k protoreflect.Kind, key string) {
+	if k != protoreflect.StringKind {
+		gf.P(`buf.WriteByte('"')`)
+	}
 	switch k {
 	case protoreflect.StringKind:
 		gf.P(`buf.WriteStringWithQuote(` + key + `)`)
-	case protoreflect.Int32Kind, protoreflect.Sint32Kind:
+	case protoreflect.BoolKind:
+		gf.P(`buf.WriteBool(` + key + `)`)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
 		gf.P(`buf.WriteInt32(` + key + `)`)
 	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
 		gf.P(`buf.WriteUint32(` + key + `)`)
-	case protoreflect.Int64Kind, protoreflect.Sint64Kind:
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
 		gf.P(`buf.WriteInt64(` + key + `)`)
 	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
 		gf.P(`buf.WriteUint64(` + key + `)`)
 	default:
 		// TODO unspported type
 	}
+	if k != protoreflect.StringKind {
+		gf.P(`buf.WriteByte('"')`)
+	}
 	g.symbolMarshal(gf, `:`)
 }
 
@@ -320,70 +421,34 @@ func (g *FastJsonpbGen) fixSymbolMarshal(gf *protogen.GeneratedFile) {
 func (g *FastJsonpbGen) mapKeyTypeName(f *protogen.Field) string {
 	key := f.Desc.MapKey()
 	switch key.Kind() {
-	case protoreflect.Int32Kind, protoreflect.Sint32Kind:
-		return "int32"
-	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
-		return "uint32"
-	case protoreflect.Int64Kind, protoreflect.Sint64Kind:
-		return "int64"
-	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-		return "uint64"
-	case protoreflect.StringKind:
-		return "string"
-	default:
-		panic("unknown type")
-	}
-}
-
-func (g *FastJsonpbGen) mapValTypeName(f *protogen.Field, needStar bool) string {
-	val := f.Desc.MapValue()
-	switch val.Kind() {
 	case protoreflect.BoolKind:
 		return "bool"
-	case protoreflect.EnumKind:
-		if e, ok := g.enumsMap[string(val.Enum().FullName())]; ok {
-			return e.GoIdent.GoName
-		}
-		panic("unknown type:" + string(val.Enum().FullName()))
-	case protoreflect.Int32Kind, protoreflect.Sint32Kind:
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
 		return "int32"
 	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
 		return "uint32"
-	case protoreflect.Int64Kind, protoreflect.Sint64Kind:
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
 		return "int64"
 	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
 		return "uint64"
-	case protoreflect.Sfixed32Kind, protoreflect.FloatKind:
-		return "float32"
-	case protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
-		return "float64"
 	case protoreflect.StringKind:
 		return "string"
-	case protoreflect.BytesKind:
-		return "[]byte"
-	case protoreflect.MessageKind:
-		if message, ok := g.messagesMap[string(val.Message().FullName())]; ok {
-			if needStar {
-				return `*` + message.GoIdent.GoName
-			} else {
-				return message.GoIdent.GoName
-			}
-		}
-		panic("unknown type:" + string(val.Message().FullName()))
-	case protoreflect.GroupKind:
-		// TODO  unspported type
 	default:
 		panic("unknown type")
 	}
-	panic("unknown type")
 }
 
-func (g *FastJsonpbGen) typeName(f *protogen.Field, needStar bool) string {
+func (g *FastJsonpbGen) mapValTypeName(gf *protogen.GeneratedFile, f *protogen.Field, needStar bool) string {
+	// map entry的第二个字段为value
+	return g.typeName(gf, f.Message.Fields[1], needStar)
+}
+
+func (g *FastJsonpbGen) typeName(gf *protogen.GeneratedFile, f *protogen.Field, needStar bool) string {
 	switch f.Desc.Kind() {
 	case protoreflect.BoolKind:
 		return "bool"
 	case protoreflect.EnumKind:
-		return f.Enum.GoIdent.GoName
+		return gf.QualifiedGoIdent(f.Enum.GoIdent)
 	case protoreflect.Int32Kind, protoreflect.Sint32Kind:
 		return "int32"
 	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
@@ -402,14 +467,13 @@ func (g *FastJsonpbGen) typeName(f *protogen.Field, needStar bool) string {
 		return "[]byte"
 	case protoreflect.MessageKind:
 		if needStar {
-			return `*` + f.Message.GoIdent.GoName
+			return `*` + gf.QualifiedGoIdent(f.Message.GoIdent)
 		} else {
-			return f.Message.GoIdent.GoName
+			return gf.QualifiedGoIdent(f.Message.GoIdent)
 		}
 	default:
 		panic("unknown type")
 	}
-	panic("unknown type")
 }
 
 // TODO 删除debug信息
@@ -426,7 +490,7 @@ func (g *FastJsonpbGen) generateDebug(message *protogen.Message, gf *protogen.Ge
 			str += ` jsonName:` + sf.Desc.JSONName()
 			if sf.Desc.IsMap() {
 				str += ` IsMap:true`
-				str += ` typeName:` + g.mapValTypeName(sf, false)
+				str += ` typeName:` + g.mapValTypeName(gf, sf, false)
 				key := sf.Desc.MapKey()
 				val := sf.Desc.MapValue()
 				str += ` mapKeyKind:` + key.Kind().String()
@@ -471,32 +535,50 @@ func (g *FastJsonpbGen) generateUnmarshal(message *protogen.Message, gf *protoge
 	gf.P(`if x == nil {`)
 	gf.P(`panic("type ` + message.GoIdent.GoName + ` is nil")`)
 	gf.P(`}`)
+	// 记录已出现的字段，同一个oneof的成员共用一位
+	n := 0
+	for _, f := range message.Fields {
+		if f.Desc.ContainingOneof() == nil {
+			n++
+		}
+	}
+	n += len(message.Oneofs)
+	if n > 0 {
+		gf.P(`var seen [` + strconv.Itoa((n+63)/64) + `]uint64`)
+	}
 	gf.P(`p.Symbol('{')`)
 	gf.P(`for !p.IsSymbol('}') {`)
-	gf.P(`key := p.Str()`)
+	gf.P(`key := p.Key()`)
 	gf.P(`p.AssertSymbol(':')`)
 	gf.P(`switch key {`)
+	idx := 0
 	// 处理simple字段
 	for _, f := range message.Fields {
 		if f.Desc.ContainingOneof() == nil {
 			if f.Desc.IsList() {
-				g.listUnmarshal(gf, f)
+				g.listUnmarshal(gf, f, idx)
 			} else if f.Desc.IsMap() {
-				g.mapUnmarshal(gf, f)
+				g.mapUnmarshal(gf, f, idx)
 			} else {
-				g.typeUnmarshal(gf, f)
+				g.typeUnmarshal(gf, f, idx)
 			}
+			idx++
 			gf.P(``)
 		}
 	}
 	// 处理oneof字段
 	for _, of := range message.Oneofs {
 		for _, f := range of.Fields {
-			g.oneofTypeUnmarshal(gf, of, f)
+			g.oneofTypeUnmarshal(gf, of, f, idx)
 		}
+		idx++
 	}
 	gf.P(`default:`)
-	gf.P(`p.PassParse()`)
+	if g.preserveUnknown {
+		gf.P(g.unknownTable(message) + `.Append(&x.unknownFields, key, p.UnknownValue(key))`)
+	} else {
+		gf.P(`p.Unknown(key)`)
+	}
 	// end switch
 	gf.P(`}`)
 	gf.P(`p.AssertSymbol(',')`)
@@ -509,14 +591,20 @@ func (g *FastJsonpbGen) generateUnmarshal(message *protogen.Message, gf *protoge
 	gf.P(``)
 }
 
-func (g *FastJsonpbGen) valUnmarshal(gf *protogen.GeneratedFile, k protoreflect.Kind, v string, typeName string) {
-	switch k {
+func (g *FastJsonpbGen) valUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field, v string) {
+	typeName := g.typeName(gf, f, false)
+	switch f.Desc.Kind() {
 	case protoreflect.BoolKind:
 		gf.P(v + ` = p.Bol()`)
 	case protoreflect.EnumKind:
+		if g.foreignEnum(f) {
+			g.foreignEnumUnmarshal(gf, f, v)
+			break
+		}
 		gf.P(`t,s,i := p.Enum()`)
 		gf.P(`if t == jsonparser.EnumNumber {`)
-		gf.P(v + `.Set(i)`)
+		// 与protojson一致，数字不检查是否定义
+		gf.P(v + ` = ` + typeName + `(i)`)
 		gf.P(`}else {`)
 		gf.P(v + `.SetByStr(s)`)
 		gf.P(`}`)
@@ -537,7 +625,21 @@ func (g *FastJsonpbGen) valUnmarshal(gf *protogen.GeneratedFile, k protoreflect.
 	case protoreflect.BytesKind:
 		gf.P(v + ` = p.Bytes()`)
 	case protoreflect.MessageKind:
-		gf.P(v + ` = ` + typeName + `New()`)
+		if g.foreignMessage(f) {
+			gf.P(`if ` + v + ` == nil {`)
+			gf.P(v + ` = new(` + typeName + `)`)
+			gf.P(`}`)
+			gf.P(fastjsonpbPkg.Ident("UnmarshalMessage"), `(p, `+v+`)`)
+			break
+		}
+		// 复用已分配的message，合并模式下保留原有数据
+		gf.P(`if ` + v + ` != nil {`)
+		gf.P(`if !p.Merge() {`)
+		gf.P(v + `.FastReset()`)
+		gf.P(`}`)
+		gf.P(`} else {`)
+		gf.P(v + ` = ` + typeName + `ArenaNew(p.Arena())`)
+		gf.P(`}`)
 		gf.P(v + `.FastUnmarshal(p)`)
 	case protoreflect.GroupKind:
 		// TODO  unspported type
@@ -546,44 +648,111 @@ func (g *FastJsonpbGen) valUnmarshal(gf *protogen.GeneratedFile, k protoreflect.
 	}
 }
 
-func (g *FastJsonpbGen) typeUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field) {
-	gf.P(`case "` + f.Desc.JSONName() + `":`)
-	g.valUnmarshal(gf, f.Desc.Kind(), `x.`+f.GoName, g.typeName(f, false))
+// 字段的message类型来自其他package，可能不是由本插件生成，通过encoding/json的方法处理，没有生成代码时使用protojson
+func (g *FastJsonpbGen) foreignMessage(f *protogen.Field) bool {
+	return f.Message != nil && f.Message.GoIdent.GoImportPath != f.Parent.GoIdent.GoImportPath
 }
 
-func (g *FastJsonpbGen) listValUnmarshal(gf *protogen.GeneratedFile, k protoreflect.Kind, v string, typeName string) {
-	switch k {
+// 字段的message类型与当前文件在同一个package，可以直接调用生成的方法
+func (g *FastJsonpbGen) localMessage(f *protogen.Field) bool {
+	return f.Message != nil && !g.foreignMessage(f)
+}
+
+// 字段的enum类型来自其他package，没有生成的Set、Get方法
+func (g *FastJsonpbGen) foreignEnum(f *protogen.Field) bool {
+	return f.Enum != nil && f.Enum.GoIdent.GoImportPath != f.Parent.GoIdent.GoImportPath
+}
+
+// 通过protoc-gen-go生成的T_value解析其他package的enum，与protojson一致，数字不检查是否定义
+func (g *FastJsonpbGen) foreignEnumUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field, v string) {
+	typeName := g.typeName(gf, f, false)
+	values := gf.QualifiedGoIdent(protogen.GoIdent{GoName: f.Enum.GoIdent.GoName + "_value", GoImportPath: f.Enum.GoIdent.GoImportPath})
+	gf.P(`t,s,i := p.Enum()`)
+	gf.P(`if t == jsonparser.EnumNumber {`)
+	gf.P(v + ` = ` + typeName + `(i)`)
+	gf.P(`} else if n, ok := ` + values + `[s]; ok {`)
+	gf.P(v + ` = ` + typeName + `(n)`)
+	gf.P(`} else {`)
+	gf.P(`panic("enum ` + f.Enum.GoIdent.GoName + ` value do not match")`)
+	gf.P(`}`)
+}
+
+// 与protojson一致，null表示未设置该字段，跳过
+func (g *FastJsonpbGen) nullUnmarshal(gf *protogen.GeneratedFile) {
+	gf.P(`if p.SkipNull() {`)
+	gf.P(`break`)
+	gf.P(`}`)
+}
+
+// 与protojson一致，同时接受json名字和proto中定义的名字
+func (g *FastJsonpbGen) caseUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field) {
+	if string(f.Desc.Name()) == f.Desc.JSONName() {
+		gf.P(`case "` + f.Desc.JSONName() + `":`)
+	} else {
+		gf.P(`case "` + f.Desc.JSONName() + `", "` + string(f.Desc.Name()) + `":`)
+	}
+}
+
+// 重复字段按照解析选项处理：报错、保留第一个或者保留最后一个
+func (g *FastJsonpbGen) duplicateUnmarshal(gf *protogen.GeneratedFile, idx int) {
+	gf.P(`if p.SkipDuplicate(seen[:], ` + strconv.Itoa(idx) + `, key) {`)
+	gf.P(`break`)
+	gf.P(`}`)
+}
+
+func (g *FastJsonpbGen) typeUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field, idx int) {
+	g.caseUnmarshal(gf, f)
+	g.duplicateUnmarshal(gf, idx)
+	g.nullUnmarshal(gf)
+	g.valUnmarshal(gf, f, `x.`+f.GoName)
+}
+
+func (g *FastJsonpbGen) listValUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field, v string) {
+	typeName := g.typeName(gf, f, false)
+	switch f.Desc.Kind() {
 	case protoreflect.BoolKind:
-		gf.P(v + ` = append(` + v + `,p.Bol())`)
+		gf.P(v + ` = p.Arena().AppendBool(` + v + `,p.Bol())`)
 	case protoreflect.EnumKind:
 		gf.P(`var e ` + typeName)
+		if g.foreignEnum(f) {
+			g.foreignEnumUnmarshal(gf, f, `e`)
+			gf.P(v + ` = append(` + v + `,e)`)
+			break
+		}
 		gf.P(`t,s,i := p.Enum()`)
 		gf.P(`if t == jsonparser.EnumNumber {`)
-		gf.P(`e = e.Get(i)`)
+		gf.P(`e = ` + typeName + `(i)`)
 		gf.P(`}else {`)
 		gf.P(`e = e.GetByStr(s)`)
 		gf.P(`}`)
 		gf.P(v + ` = append(` + v + `,e)`)
 	case protoreflect.Int32Kind, protoreflect.Sint32Kind:
-		gf.P(v + ` = append(` + v + `,p.Int32())`)
+		gf.P(v + ` = p.Arena().AppendInt32(` + v + `,p.Int32())`)
 	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
-		gf.P(v + ` = append(` + v + `,p.Uint32())`)
+		gf.P(v + ` = p.Arena().AppendUint32(` + v + `,p.Uint32())`)
 	case protoreflect.Int64Kind, protoreflect.Sint64Kind:
-		gf.P(v + ` = append(` + v + `,p.Int64())`)
+		gf.P(v + ` = p.Arena().AppendInt64(` + v + `,p.Int64())`)
 	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-		gf.P(v + ` = append(` + v + `,p.Uint64())`)
+		gf.P(v + ` = p.Arena().AppendUint64(` + v + `,p.Uint64())`)
 	case protoreflect.Sfixed32Kind, protoreflect.FloatKind:
-		gf.P(v + ` = append(` + v + `,p.Float32())`)
+		gf.P(v + ` = p.Arena().AppendFloat32(` + v + `,p.Float32())`)
 	case protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
-		gf.P(v + ` = append(` + v + `,p.Float64())`)
+		gf.P(v + ` = p.Arena().AppendFloat64(` + v + `,p.Float64())`)
 	case protoreflect.StringKind:
-		gf.P(v + ` = append(` + v + `,p.Str())`)
+		gf.P(v + ` = p.Arena().AppendString(` + v + `,p.Str())`)
 	case protoreflect.BytesKind:
-		gf.P(v + ` = append(` + v + `,p.Bytes())`)
+		gf.P(v + ` = p.Arena().AppendBytes(` + v + `,p.Bytes())`)
 	case protoreflect.MessageKind:
-		gf.P(`tmp := ` + typeName + `New()`)
+		if g.foreignMessage(f) {
+			gf.P(`tmp := new(` + typeName + `)`)
+			gf.P(fastjsonpbPkg.Ident("UnmarshalMessage"), `(p, tmp)`)
+			gf.P(v + ` = append(` + v + `,tmp)`)
+			break
+		}
+		// Destructor已经把数组中的message放回Pool，这里从Pool获取
+		gf.P(`tmp := ` + typeName + `ArenaNew(p.Arena())`)
 		gf.P(`tmp.FastUnmarshal(p)`)
-		gf.P(v + ` = append(` + v + `,tmp)`)
+		gf.P(v + ` = ` + typeName + `ArenaAppend(p.Arena(),` + v + `,tmp)`)
 	case protoreflect.GroupKind:
 		// TODO  unspported type
 	default:
@@ -591,12 +760,19 @@ func (g *FastJsonpbGen) listValUnmarshal(gf *protogen.GeneratedFile, k protorefl
 	}
 }
 
-func (g *FastJsonpbGen) listUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field) {
-	gf.P(`case "` + f.Desc.JSONName() + `":`)
+func (g *FastJsonpbGen) listUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field, idx int) {
+	g.caseUnmarshal(gf, f)
+	g.duplicateUnmarshal(gf, idx)
+	g.nullUnmarshal(gf)
 	gf.P(`p.Symbol('[')`)
-	gf.P(`arr := make([]` + g.typeName(f, true) + `,0)`)
+	// 合并模式下在原有元素后追加
+	// 否则只复用Destructor保留的空数组，有元素的数组可能与调用方共享底层数组，不能覆盖
+	gf.P(`arr := x.` + f.GoName)
+	gf.P(`if !p.Merge() && len(arr) > 0 {`)
+	gf.P(`arr = nil`)
+	gf.P(`}`)
 	gf.P(`for !p.IsSymbol(']') {`)
-	g.listValUnmarshal(gf, f.Desc.Kind(), `arr`, g.typeName(f, false))
+	g.listValUnmarshal(gf, f, `arr`)
 	gf.P(`p.AssertSymbol(',')`)
 	//end for
 	gf.P(`}`)
@@ -605,15 +781,21 @@ func (g *FastJsonpbGen) listUnmarshal(gf *protogen.GeneratedFile, f *protogen.Fi
 	gf.P(`x.` + f.GoName + ` = arr`)
 }
 
-func (g *FastJsonpbGen) mapValUnmarshal(gf *protogen.GeneratedFile, k protoreflect.Kind, v string, typeName string) {
-	switch k {
+func (g *FastJsonpbGen) mapValUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field, v string) {
+	typeName := g.typeName(gf, f, false)
+	switch f.Desc.Kind() {
 	case protoreflect.BoolKind:
 		gf.P(v + ` = p.Bol()`)
 	case protoreflect.EnumKind:
 		gf.P(`var e ` + typeName)
+		if g.foreignEnum(f) {
+			g.foreignEnumUnmarshal(gf, f, `e`)
+			gf.P(v + ` = e`)
+			break
+		}
 		gf.P(`t,s,i := p.Enum()`)
 		gf.P(`if t == jsonparser.EnumNumber {`)
-		gf.P(`e = e.Get(i)`)
+		gf.P(`e = ` + typeName + `(i)`)
 		gf.P(`}else {`)
 		gf.P(`e = e.GetByStr(s)`)
 		gf.P(`}`)
@@ -635,8 +817,13 @@ func (g *FastJsonpbGen) mapValUnmarshal(gf *protogen.GeneratedFile, k protorefle
 	case protoreflect.BytesKind:
 		gf.P(v + ` = p.Bytes()`)
 	case protoreflect.MessageKind:
-		gf.P(`tmp := ` + typeName + `New()`)
-		gf.P(`tmp.FastUnmarshal(p)`)
+		if g.foreignMessage(f) {
+			gf.P(`tmp := new(` + typeName + `)`)
+			gf.P(fastjsonpbPkg.Ident("UnmarshalMessage"), `(p, tmp)`)
+		} else {
+			gf.P(`tmp := ` + typeName + `ArenaNew(p.Arena())`)
+			gf.P(`tmp.FastUnmarshal(p)`)
+		}
 		gf.P(v + ` = tmp`)
 	case protoreflect.GroupKind:
 		// TODO  unspported type
@@ -645,14 +832,37 @@ func (g *FastJsonpbGen) mapValUnmarshal(gf *protogen.GeneratedFile, k protorefle
 	}
 }
 
-func (g *FastJsonpbGen) mapUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field) {
-	gf.P(`case "` + f.Desc.JSONName() + `":`)
+func (g *FastJsonpbGen) mapUnmarshal(gf *protogen.GeneratedFile, f *protogen.Field, idx int) {
+	g.caseUnmarshal(gf, f)
+	g.duplicateUnmarshal(gf, idx)
+	g.nullUnmarshal(gf)
 	gf.P(`p.Symbol('{')`)
-	gf.P(`m := make(map[` + g.mapKeyTypeName(f) + `]` + g.mapValTypeName(f, true) + `)`)
+	// 合并模式下保留原有key
+	// 否则只复用Destructor保留的空map，有元素的map可能与调用方共享，不能修改
+	gf.P(`m := x.` + f.GoName)
+	gf.P(`if m == nil || (!p.Merge() && len(m) > 0) {`)
+	gf.P(`m = make(map[` + g.mapKeyTypeName(f) + `]` + g.mapValTypeName(gf, f, true) + `)`)
+	gf.P(`}`)
+	// 当前对象中已经出现的key，合并模式下m中原有的key不算重复
+	gf.P(`var keys map[` + g.mapKeyTypeName(f) + `]struct{}`)
 	gf.P(`for !p.IsSymbol('}') {`)
-	gf.P(`key := p.Str()`)
+	g.mapKeyUnmarshal(gf, f.Desc.MapKey().Kind())
 	gf.P(`p.AssertSymbol(':')`)
-	g.mapValUnmarshal(gf, f.Desc.MapValue().Kind(), `m[key]`, g.mapValTypeName(f, false))
+	gf.P(`if p.CheckDuplicate() {`)
+	if f.Desc.MapKey().Kind() == protoreflect.StringKind {
+		gf.P(`if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {`)
+	} else {
+		gf.P(`if _, ok := keys[key]; ok && p.SkipDuplicateKey(p.Raw()) {`)
+	}
+	gf.P(`p.AssertSymbol(',')`)
+	gf.P(`continue`)
+	gf.P(`}`)
+	gf.P(`if keys == nil {`)
+	gf.P(`keys = make(map[` + g.mapKeyTypeName(f) + `]struct{})`)
+	gf.P(`}`)
+	gf.P(`keys[key] = struct{}{}`)
+	gf.P(`}`)
+	g.mapValUnmarshal(gf, f.Message.Fields[1], `m[key]`)
 	gf.P(`p.AssertSymbol(',')`)
 	//end for
 	gf.P(`}`)
@@ -661,13 +871,62 @@ func (g *FastJsonpbGen) mapUnmarshal(gf *protogen.GeneratedFile, f *protogen.Fie
 	gf.P(`x.` + f.GoName + ` = m`)
 }
 
-func (g *FastJsonpbGen) oneofTypeUnmarshal(gf *protogen.GeneratedFile, of *protogen.Oneof, f *protogen.Field) {
-	gf.P(`case "` + f.Desc.JSONName() + `":`)
-	gf.P(`tmp := &` + f.GoIdent.GoName + `{}`)
-	g.valUnmarshal(gf, f.Desc.Kind(), `tmp.`+f.GoName, g.typeName(f, false))
+// 读取map的key，非string类型的key为加引号的字符串
+func (g *FastJsonpbGen) mapKeyUnmarshal(gf *protogen.GeneratedFile, k protoreflect.Kind) {
+	switch k {
+	case protoreflect.BoolKind:
+		gf.P(`key := p.BolKey()`)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		gf.P(`key := p.Int32()`)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		gf.P(`key := p.Uint32()`)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		gf.P(`key := p.Int64()`)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		gf.P(`key := p.Uint64()`)
+	default:
+		gf.P(`key := p.Str()`)
+	}
+}
+
+func (g *FastJsonpbGen) oneofTypeUnmarshal(gf *protogen.GeneratedFile, of *protogen.Oneof, f *protogen.Field, idx int) {
+	g.caseUnmarshal(gf, f)
+	g.duplicateUnmarshal(gf, idx)
+	g.nullUnmarshal(gf)
+	// 已经是同一个成员时复用，message成员按照合并模式处理
+	gf.P(`tmp, ok := x.` + of.GoName + `.(*` + f.GoIdent.GoName + `)`)
+	gf.P(`if !ok {`)
+	gf.P(`tmp = &` + f.GoIdent.GoName + `{}`)
+	gf.P(`}`)
+	g.valUnmarshal(gf, f, `tmp.`+f.GoName)
 	gf.P(`x.` + of.GoName + ` = tmp`)
 }
 
+// 生成MarshalJSON、UnmarshalJSON，message嵌入普通struct时经过encoding/json、jsoniter也使用相同的格式
+func (g *FastJsonpbGen) generateJSONMethods(message *protogen.Message, gf *protogen.GeneratedFile) {
+	gf.P(`func (x *` + message.GoIdent.GoName + `) MarshalJSON() ([]byte, error) {`)
+	gf.P(`return `, fastjsonpbPkg.Ident("Marshal"), `(x)`)
+	gf.P(`}`)
+	gf.P(``)
+	gf.P(`func (x *` + message.GoIdent.GoName + `) UnmarshalJSON(b []byte) error {`)
+	gf.P(`return `, fastjsonpbPkg.Ident("Unmarshal"), `(b, x)`)
+	gf.P(`}`)
+	gf.P(``)
+}
+
+// 生成jsonpb.JSONPBMarshaler、jsonpb.JSONPBUnmarshaler接口，旧版jsonpb的调用方无需修改即可使用快速路径
+func (g *FastJsonpbGen) generateJSONPBMethods(message *protogen.Message, gf *protogen.GeneratedFile) {
+	jsonpb := protogen.GoImportPath("github.com/golang/protobuf/jsonpb")
+	gf.P(`func (x *`+message.GoIdent.GoName+`) MarshalJSONPB(m *`, jsonpb.Ident("Marshaler"), `) ([]byte, error) {`)
+	gf.P(`return `, jsonpbcompatPkg.Ident("MarshalJSONPB"), `(m, x)`)
+	gf.P(`}`)
+	gf.P(``)
+	gf.P(`func (x *`+message.GoIdent.GoName+`) UnmarshalJSONPB(u *`, jsonpb.Ident("Unmarshaler"), `, b []byte) error {`)
+	gf.P(`return `, jsonpbcompatPkg.Ident("UnmarshalJSONPB"), `(u, b, x)`)
+	gf.P(`}`)
+	gf.P(``)
+}
+
 // 生成判空方法
 func (g *FastJsonpbGen) generateEmpty(message *protogen.Message, gf *protogen.GeneratedFile) {
 	// 处理simple字段
@@ -675,7 +934,8 @@ func (g *FastJsonpbGen) generateEmpty(message *protogen.Message, gf *protogen.Ge
 		if f.Desc.ContainingOneof() == nil {
 			gf.P(`func (x *` + message.GoIdent.GoName + `) IsEmpty` + f.GoName + `() bool {`)
 			if f.Desc.IsList() || f.Desc.IsMap() {
-				gf.P(`return x.Get` + f.GoName + `() == nil`)
+				// Destructor后数组、map不为nil但长度为0
+				gf.P(`return len(x.Get` + f.GoName + `()) == 0`)
 			} else {
 				switch f.Desc.Kind() {
 				case protoreflect.BoolKind:
@@ -686,7 +946,9 @@ func (g *FastJsonpbGen) generateEmpty(message *protogen.Message, gf *protogen.Ge
 					gf.P(`return x.Get` + f.GoName + `() == 0`)
 				case protoreflect.StringKind:
 					gf.P(`return x.Get` + f.GoName + `() == ""`)
-				case protoreflect.BytesKind, protoreflect.MessageKind:
+				case protoreflect.BytesKind:
+					gf.P(`return len(x.Get` + f.GoName + `()) == 0`)
+				case protoreflect.MessageKind:
 					gf.P(`return x.Get` + f.GoName + `() == nil`)
 				case protoreflect.GroupKind:
 					// TODO  unspported type
@@ -710,6 +972,91 @@ func (g *FastJsonpbGen) generatePool(message *protogen.Message, gf *protogen.Gen
 	gf.P(`}`)
 	gf.P(`return &` + message.GoIdent.GoName + `{}`)
 	gf.P(`}`)
+	if g.preserveUnknown {
+		// 未知字段的原始JSON保存在单独的表中，不写入protobuf的unknownFields
+		gf.P(`var ` + g.unknownTable(message) + ` jsonparser.UnknownTable`)
+	}
+}
+
+func (g *FastJsonpbGen) unknownTable(message *protogen.Message) string {
+	return `fastjsonpb` + message.GoIdent.GoName + `Unknown`
+}
+
+// 生成init方法，按完整名字注册文件中的message，供UnmarshalByName等使用
+func (g *FastJsonpbGen) generateRegister(protoFile *protogen.File, gf *protogen.GeneratedFile) {
+	var messages []*protogen.Message
+	var walk func([]*protogen.Message)
+	walk = func(ms []*protogen.Message) {
+		for _, m := range ms {
+			if m.Desc.IsMapEntry() {
+				continue
+			}
+			messages = append(messages, m)
+			walk(m.Messages)
+		}
+	}
+	walk(protoFile.Messages)
+	if len(messages) == 0 {
+		return
+	}
+	protoMessage := protogen.GoImportPath("google.golang.org/protobuf/proto").Ident("Message")
+	gf.P(`func init() {`)
+	for _, m := range messages {
+		name := m.GoIdent.GoName
+		gf.P(fastjsonpbPkg.Ident("RegisterType"), `("`+string(m.Desc.FullName())+`", func() `, protoMessage, ` { return `+name+`New() }, func(m `, protoMessage, `) { m.(*`+name+`).Destructor() })`)
+	}
+	gf.P(`}`)
+	gf.P(``)
+}
+
+// 生成Arena分配方法
+func (g *FastJsonpbGen) generateArena(message *protogen.Message, gf *protogen.GeneratedFile) {
+	goName := message.GoIdent.GoName
+	slab := `fastjsonpb` + goName + `Slab`
+	gf.P(`type ` + slab + ` struct {`)
+	gf.P(`msgs []` + goName)
+	gf.P(`ptrs []*` + goName)
+	gf.P(`}`)
+	gf.P(``)
+	gf.P(`var ` + slab + `ID = arena.NewSlabID()`)
+	gf.P(``)
+	gf.P(`func ` + slab + `Get(a *arena.Arena) *` + slab + ` {`)
+	gf.P(`s, _ := a.Slab(` + slab + `ID).(*` + slab + `)`)
+	gf.P(`if s == nil {`)
+	gf.P(`s = &` + slab + `{}`)
+	gf.P(`a.SetSlab(` + slab + `ID, s)`)
+	gf.P(`}`)
+	gf.P(`return s`)
+	gf.P(`}`)
+	gf.P(``)
+	gf.P(`func ` + goName + `ArenaNew(a *arena.Arena) *` + goName + ` {`)
+	gf.P(`if a == nil {`)
+	gf.P(`return ` + goName + `New()`)
+	gf.P(`}`)
+	gf.P(`s := ` + slab + `Get(a)`)
+	gf.P(`if len(s.msgs) == cap(s.msgs) {`)
+	gf.P(`s.msgs = make([]` + goName + `, 0, arena.SlabLen(cap(s.msgs)))`)
+	gf.P(`}`)
+	gf.P(`s.msgs = s.msgs[:len(s.msgs)+1]`)
+	gf.P(`return &s.msgs[len(s.msgs)-1]`)
+	gf.P(`}`)
+	gf.P(``)
+	gf.P(`func ` + goName + `ArenaAppend(a *arena.Arena, arr []*` + goName + `, v *` + goName + `) []*` + goName + ` {`)
+	gf.P(`if a == nil || len(arr) < cap(arr) {`)
+	gf.P(`return append(arr, v)`)
+	gf.P(`}`)
+	gf.P(`s := ` + slab + `Get(a)`)
+	gf.P(`n := arena.GrowLen(cap(arr))`)
+	gf.P(`if cap(s.ptrs)-len(s.ptrs) < n {`)
+	gf.P(`s.ptrs = make([]*` + goName + `, 0, arena.ChunkLen(cap(s.ptrs), n))`)
+	gf.P(`}`)
+	gf.P(`l := len(s.ptrs)`)
+	gf.P(`s.ptrs = s.ptrs[:l+n]`)
+	gf.P(`ns := s.ptrs[l : l+len(arr) : l+n]`)
+	gf.P(`copy(ns, arr)`)
+	gf.P(`return append(ns, v)`)
+	gf.P(`}`)
+	gf.P(``)
 }
 
 // 生成enum相关方法
@@ -720,18 +1067,20 @@ func (g *FastJsonpbGen) generateEnum(e *protogen.Enum, gf *protogen.GeneratedFil
 
 func (g *FastJsonpbGen) generateEnumSetter(e *protogen.Enum, gf *protogen.GeneratedFile) {
 	goName := e.GoIdent.GoName
-	gf.P(`func (x ` + goName + `) Set(i int32) {`)
+	gf.P(`// Set 设置为数值i，i不是定义过的值时panic`)
+	gf.P(`func (x *` + goName + `) Set(i int32) {`)
 	gf.P(`if _,ok := ` + goName + `_name[i]; ok {`)
-	gf.P(`x = ` + goName + `(i)`)
+	gf.P(`*x = ` + goName + `(i)`)
 	gf.P(`return`)
 	gf.P(`}`)
 	gf.P(`panic("enum ` + goName + `value do not match")`)
 	gf.P(`}`)
 	gf.P(``)
 
-	gf.P(`func (x ` + goName + `) SetByStr(s string) {`)
+	gf.P(`// SetByStr 设置为名字s对应的值，s不是定义过的名字时panic`)
+	gf.P(`func (x *` + goName + `) SetByStr(s string) {`)
 	gf.P(`if i,ok := ` + goName + `_value[s]; ok {`)
-	gf.P(`x = ` + goName + `(i)`)
+	gf.P(`*x = ` + goName + `(i)`)
 	gf.P(`return`)
 	gf.P(`}`)
 	gf.P(`panic("enum ` + goName + `value do not match")`)
@@ -758,9 +1107,25 @@ func (g *FastJsonpbGen) generateEnumGetter(e *protogen.Enum, gf *protogen.Genera
 	gf.P(``)
 }
 
-// 生成Reset方法
+// 生成Reset、Destructor方法
 func (g *FastJsonpbGen) generateDestructor(message *protogen.Message, gf *protogen.GeneratedFile) {
+	// 清空字段，不把嵌套的message放回Pool，反序列化前调用
+	// 有元素的数组、map可能与调用方共享，设置为nil；Destructor留下的空数组、空map保留，反序列化时复用其空间
+	gf.P(`func (x *` + message.GoIdent.GoName + `) FastReset() {`)
+	g.resetFields(message, gf, false)
+	gf.P(`}`)
+	gf.P(``)
+
+	// 清空字段并把嵌套的message放回Pool，数组、map保留已分配的空间
 	gf.P(`func (x *` + message.GoIdent.GoName + `) Destructor() {`)
+	g.resetFields(message, gf, true)
+	gf.P(message.GoIdent.GoName + `Pool.Put(x)`)
+	gf.P(`}`)
+	gf.P(``)
+}
+
+// recycle为true时回收嵌套的message并保留数组、map的空间
+func (g *FastJsonpbGen) resetFields(message *protogen.Message, gf *protogen.GeneratedFile, recycle bool) {
 	gf.P(`if x == nil {`)
 	gf.P(`panic("type ` + message.GoIdent.GoName + ` is nil")`)
 	gf.P(`}`)
@@ -768,11 +1133,11 @@ func (g *FastJsonpbGen) generateDestructor(message *protogen.Message, gf *protog
 	for _, f := range message.Fields {
 		if f.Desc.ContainingOneof() == nil {
 			if f.Desc.IsList() {
-				g.listDestructor(gf, f)
+				g.listDestructor(gf, f, recycle)
 			} else if f.Desc.IsMap() {
-				g.mapDestructor(gf, f)
+				g.mapDestructor(gf, f, recycle)
 			} else {
-				g.typeDestructor(gf, f)
+				g.typeDestructor(gf, f, recycle)
 			}
 		}
 	}
@@ -780,7 +1145,7 @@ func (g *FastJsonpbGen) generateDestructor(message *protogen.Message, gf *protog
 	for _, of := range message.Oneofs {
 		oneofs := make([]*protogen.Field, 0)
 		for _, osf := range of.Fields {
-			if osf.Desc.Kind() == protoreflect.MessageKind {
+			if recycle && g.localMessage(osf) {
 				oneofs = append(oneofs, osf)
 			}
 		}
@@ -801,19 +1166,18 @@ func (g *FastJsonpbGen) generateDestructor(message *protogen.Message, gf *protog
 		gf.P(`x.` + of.GoName + ` = nil`)
 		gf.P(`}`)
 	}
-	gf.P(message.GoIdent.GoName + `Pool.Put(x)`)
-	// end func
-	gf.P(`}`)
-	gf.P(``)
+	if g.preserveUnknown {
+		gf.P(g.unknownTable(message) + `.Reset(x.unknownFields)`)
+	}
 }
 
-func (g *FastJsonpbGen) typeDestructor(gf *protogen.GeneratedFile, f *protogen.Field) {
+func (g *FastJsonpbGen) typeDestructor(gf *protogen.GeneratedFile, f *protogen.Field, recycle bool) {
 	v := `x.` + f.GoName
 	switch f.Desc.Kind() {
 	case protoreflect.BoolKind:
 		gf.P(v + ` = false`)
 	case protoreflect.EnumKind:
-		gf.P(v + `.Set(0)`)
+		gf.P(v + ` = 0`)
 	case protoreflect.Int32Kind, protoreflect.Sint32Kind:
 		gf.P(v + ` = 0`)
 	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
@@ -831,8 +1195,14 @@ func (g *FastJsonpbGen) typeDestructor(gf *protogen.GeneratedFile, f *protogen.F
 	case protoreflect.BytesKind:
 		gf.P(v + ` = nil`)
 	case protoreflect.MessageKind:
+		if !recycle || g.foreignMessage(f) {
+			gf.P(v + ` = nil`)
+			break
+		}
+		gf.P(`if ` + v + ` != nil {`)
 		gf.P(v + `.Destructor()`)
 		gf.P(v + ` = nil`)
+		gf.P(`}`)
 	case protoreflect.GroupKind:
 		// TODO  unspported type
 	default:
@@ -840,22 +1210,46 @@ func (g *FastJsonpbGen) typeDestructor(gf *protogen.GeneratedFile, f *protogen.F
 	}
 }
 
-func (g *FastJsonpbGen) listDestructor(gf *protogen.GeneratedFile, f *protogen.Field) {
-	if f.Desc.Kind() == protoreflect.MessageKind {
+func (g *FastJsonpbGen) listDestructor(gf *protogen.GeneratedFile, f *protogen.Field, recycle bool) {
+	if !recycle {
+		gf.P(`if len(x.` + f.GoName + `) > 0 {`)
+		gf.P(`x.` + f.GoName + ` = nil`)
+		gf.P(`}`)
+		return
+	}
+	switch {
+	case g.localMessage(f):
+		// 底层数组中不保留message，反序列化时从Pool获取
 		gf.P(`for i,_ := range x.` + f.GoName + `{`)
 		gf.P(`x.` + f.GoName + `[i].Destructor()`)
+		gf.P(`x.` + f.GoName + `[i] = nil`)
+		gf.P(`}`)
+	case f.Desc.Kind() == protoreflect.StringKind:
+		// 避免底层数组继续引用旧数据
+		gf.P(`for i,_ := range x.` + f.GoName + `{`)
+		gf.P(`x.` + f.GoName + `[i] = ""`)
+		gf.P(`}`)
+	case f.Desc.Kind() == protoreflect.BytesKind, f.Desc.Kind() == protoreflect.MessageKind:
+		gf.P(`for i,_ := range x.` + f.GoName + `{`)
+		gf.P(`x.` + f.GoName + `[i] = nil`)
 		gf.P(`}`)
 	}
-	gf.P(`x.` + f.GoName + ` = nil`)
+	gf.P(`x.` + f.GoName + ` = x.` + f.GoName + `[:0]`)
 }
 
-func (g *FastJsonpbGen) mapDestructor(gf *protogen.GeneratedFile, f *protogen.Field) {
-	if f.Desc.MapValue().Kind() == protoreflect.MessageKind {
-		gf.P(`for i,_ := range x.` + f.GoName + `{`)
-		gf.P(`x.` + f.GoName + `[i].Destructor()`)
+func (g *FastJsonpbGen) mapDestructor(gf *protogen.GeneratedFile, f *protogen.Field, recycle bool) {
+	if !recycle {
+		gf.P(`if len(x.` + f.GoName + `) > 0 {`)
+		gf.P(`x.` + f.GoName + ` = nil`)
 		gf.P(`}`)
+		return
+	}
+	gf.P(`for k,_ := range x.` + f.GoName + `{`)
+	if g.localMessage(f.Message.Fields[1]) {
+		gf.P(`x.` + f.GoName + `[k].Destructor()`)
 	}
-	gf.P(`x.` + f.GoName + ` = nil`)
+	gf.P(`delete(x.` + f.GoName + `, k)`)
+	gf.P(`}`)
 }
 
 func (g *FastJsonpbGen) oneofTypeDestructor(gf *protogen.GeneratedFile, of *protogen.Oneof, f *protogen.Field, prefix string) {
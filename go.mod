@@ -4,4 +4,6 @@ go 1.17
 
 require github.com/golang/protobuf v1.5.2
 
-require google.golang.org/protobuf v1.26.0 // indirect
+require google.golang.org/protobuf v1.28.0
+
+require github.com/google/go-cmp v0.5.8 // indirect
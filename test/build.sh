@@ -28,7 +28,13 @@ compile_proto_list=( \
 for var in ${compile_proto_list[@]};
 do
     $PROTOC --go_out=$HOME_DIR \
-        --fastjsonpb_out=$HOME_DIR \
+        --fastjsonpb_out=preserve_unknown=true:$HOME_DIR \
         --proto_path=$HOME_DIR/test \
         $HOME_DIR/test/$var
 done
+
+# 生成MarshalJSON、UnmarshalJSON，用于嵌入普通struct；生成MarshalJSONPB、UnmarshalJSONPB，用于旧版jsonpb
+$PROTOC --go_out=$HOME_DIR \
+    --fastjsonpb_out=json_methods=true,jsonpb_methods=true:$HOME_DIR \
+    --proto_path=$HOME_DIR/test \
+    $HOME_DIR/test/embed/embed.proto
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	fastjsonpb "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/json"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/test/example"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/test/example/embed"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/jsonparser"
+	jsonpb "google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/typepb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func fullExample() *example.Example {
+	return &example.Example{
+		Bol:          true,
+		Str:          "s\"tr<>",
+		In32:         -32,
+		In64:         -1 << 60,
+		Uin32:        1 << 31,
+		Uin64:        1 << 63,
+		Flt32:        32.125,
+		Flt64:        1e-300,
+		Byts:         []byte("bytes"),
+		Typ:          example.Typ(2),
+		Msg:          &example.Msg{Str: "m", In64: 64},
+		BolArr:       []bool{true, false},
+		StrArr:       []string{"a", ""},
+		In64Arr:      []int64{1, -2},
+		Uin64Arr:     []uint64{3},
+		Flt32Arr:     []float32{0.5},
+		BytsArr:      [][]byte{[]byte("x"), nil},
+		TypArr:       []example.Typ{example.Typ(1), example.Typ(2)},
+		MsgArr:       []*example.Msg{{Bol: true}, {}},
+		BolMap:       map[string]bool{"t": true, "f": false},
+		StringMap:    map[string]string{"b": "1", "a": "2"},
+		In64Map:      map[string]int64{"k": 1 << 40},
+		BytsMap:      map[string][]byte{"k": []byte("v")},
+		TypMap:       map[string]example.Typ{"k": example.Typ(1)},
+		MsgMap:       map[string]*example.Msg{"x": {Str: "x"}, "y": {}},
+		NestedTyp:    example.Example_NestedTyp(1),
+		NestedMsg:    &example.Example_NestedMsg{Str: "n"},
+		NestedMsgMap: map[string]*example.Example_NestedMsg{"n": {Str: "n"}},
+		TestOneof:    &example.Example_OneofMsg{OneofMsg: &example.Msg{In32: 1}},
+	}
+}
+
+// 通过wire格式转换为dynamicpb
+func toDynamic(t testing.TB, m proto.Message) *dynamicpb.Message {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dyn := dynamicpb.NewMessage(m.ProtoReflect().Descriptor())
+	if err := proto.Unmarshal(b, dyn); err != nil {
+		t.Fatal(err)
+	}
+	return dyn
+}
+
+func TestDynamicMarshal(t *testing.T) {
+	item := &embed.Item{
+		Name:      "n",
+		Id:        1,
+		Child:     &embed.Item{Name: "c"},
+		Created:   timestamppb.New(time.Unix(1, 0)),
+		Tags:      []*wrapperspb.StringValue{wrapperspb.String("a")},
+		Timeouts:  map[string]*durationpb.Duration{"read": durationpb.New(time.Second)},
+		Syntax:    typepb.Syntax_SYNTAX_PROTO3,
+		SyntaxMap: map[string]typepb.Syntax{"k": typepb.Syntax_SYNTAX_PROTO3},
+		Value:     &embed.Item_Num{Num: wrapperspb.Int64(1)},
+	}
+	msgs := []proto.Message{fullExample(), &example.Example{}, orderedMsg(), item}
+	opts := []fastjsonpb.MarshalOptions{
+		{Deterministic: true},
+		{Deterministic: true, NumberOrder: true},
+		{Deterministic: true, UseProtoNames: true, UseEnumNumbers: true, EmitUnpopulated: true, QuoteInt64: true},
+		{Deterministic: true, EscapeHTML: true, ASCIIOnly: true},
+		{Canonical: true},
+	}
+	for _, m := range msgs {
+		dyn := toDynamic(t, m)
+		for _, o := range opts {
+			want, err := o.Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := o.Marshal(dyn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("%+v\n got %s\nwant %s", o, got, want)
+			}
+		}
+
+		// 反序列化后与原message相同
+		b, _ := fastjsonpb.Marshal(m)
+		dyn = dynamicpb.NewMessage(m.ProtoReflect().Descriptor())
+		if err := (fastjsonpb.UnmarshalOptions{}).Unmarshal(b, dyn); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(dyn, toDynamic(t, m)) {
+			t.Errorf("decoded %v from %s", dyn, b)
+		}
+		// protojson的输出同样可以解析
+		b, _ = jsonpb.Marshal(m)
+		dyn = dynamicpb.NewMessage(m.ProtoReflect().Descriptor())
+		if err := fastjsonpb.Unmarshal(b, dyn); err != nil || !proto.Equal(dyn, toDynamic(t, m)) {
+			t.Errorf("%v: decoded %v from %s", err, dyn, b)
+		}
+	}
+}
+
+func TestDynamicUnmarshal(t *testing.T) {
+	md := (&example.Example{}).ProtoReflect().Descriptor()
+	dyn := toDynamic(t, fullExample())
+	// 默认先清空message
+	if err := (fastjsonpb.UnmarshalOptions{NullAsUnset: true}).Unmarshal([]byte(`{"str":"a","oneof_bol":true,"msgArr":null}`), dyn); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(dyn, toDynamic(t, &example.Example{Str: "a", TestOneof: &example.Example_OneofBol{OneofBol: true}})) {
+		t.Errorf("reset: %v", dyn)
+	}
+
+	// 合并模式
+	dyn = toDynamic(t, fullExample())
+	in := `{"str":"b","msg":{"bol":true},"strArr":["c"],"stringMap":{"a":"3","c":"4"},"oneofStr":"o"}`
+	if err := (fastjsonpb.UnmarshalOptions{Merge: true}).Unmarshal([]byte(in), dyn); err != nil {
+		t.Fatal(err)
+	}
+	want := fullExample()
+	proto.Merge(want, &example.Example{
+		Str:       "b",
+		Msg:       &example.Msg{Bol: true},
+		StrArr:    []string{"c"},
+		StringMap: map[string]string{"a": "3", "c": "4"},
+		TestOneof: &example.Example_OneofStr{OneofStr: "o"},
+	})
+	if !proto.Equal(dyn, toDynamic(t, want)) {
+		t.Errorf("merge: %v", dyn)
+	}
+
+	for _, c := range []struct {
+		in   string
+		opts fastjsonpb.UnmarshalOptions
+		err  error
+		msg  string
+	}{
+		{in: `{"msg":{"foo":1}}`, err: jsonparser.ErrUnknownField, msg: `"msg.foo"`},
+		{in: `{"str":"a","str":"b"}`, opts: fastjsonpb.UnmarshalOptions{Duplicate: jsonparser.DuplicateError}, err: jsonparser.ErrDuplicateKey},
+		{in: `{"oneofBol":true,"oneofStr":"a"}`, opts: fastjsonpb.UnmarshalOptions{Duplicate: jsonparser.DuplicateError}, err: jsonparser.ErrDuplicateKey},
+		{in: `{"typ":"TYPX"}`, msg: "enum example.Typ value do not match"},
+		{in: `{"in32":1.5}`},
+		{in: `{"msgArr":[{}`},
+	} {
+		c.opts.DiscardUnknown = c.err != jsonparser.ErrUnknownField
+		err := c.opts.Unmarshal([]byte(c.in), dynamicpb.NewMessage(md))
+		if err == nil || (c.err != nil && !errors.Is(err, c.err)) || !strings.Contains(err.Error(), c.msg) {
+			t.Errorf("%s: %v", c.in, err)
+		}
+	}
+}
+
+// 与protojson一致，未定义的enum值输出为数字，反序列化时接受未定义的数字
+func TestUndefinedEnum(t *testing.T) {
+	m := &example.Example{Typ: 7, TypArr: []example.Typ{1, 8}, TypMap: map[string]example.Typ{"a": 9}}
+	want, _ := jsonpb.Marshal(m)
+	for _, v := range []proto.Message{m, toDynamic(t, m)} {
+		ret, err := fastjsonpb.Marshal(v)
+		if err != nil || !jsonEqual(t, ret, want) {
+			t.Fatalf("%v %s, want %s", err, ret, want)
+		}
+		back := v.ProtoReflect().New().Interface()
+		if err := fastjsonpb.Unmarshal(ret, back); err != nil || !proto.Equal(back, v) {
+			t.Fatalf("%v %v", err, back)
+		}
+	}
+}
+
+func BenchmarkDynamicMarshal(b *testing.B) {
+	dyn := toDynamic(b, e)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fastjsonpb.Marshal(dyn)
+	}
+}
+
+func BenchmarkDynamicProtojsonMarshal(b *testing.B) {
+	dyn := toDynamic(b, e)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		jsonpb.Marshal(dyn)
+	}
+}
+
+func BenchmarkDynamicUnmarshal(b *testing.B) {
+	md := e.ProtoReflect().Descriptor()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fastjsonpb.Unmarshal(byts, dynamicpb.NewMessage(md))
+	}
+}
+
+func BenchmarkDynamicProtojsonUnmarshal(b *testing.B) {
+	md := e.ProtoReflect().Descriptor()
+	opts := jsonpb.UnmarshalOptions{DiscardUnknown: true}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		opts.Unmarshal(byts, dynamicpb.NewMessage(md))
+	}
+}
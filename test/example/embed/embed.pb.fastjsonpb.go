@@ -0,0 +1,993 @@
+// Code generated by protoc-gen-fastjsonpb. DO NOT EDIT.
+// source:embed/embed.proto
+
+package embed
+
+import (
+	jsonpb "github.com/golang/protobuf/jsonpb"
+	json "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/json"
+	jsonpbcompat "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/jsonpbcompat"
+	arena "github.com/superjsf2010/protoc-gen-fastjsonpb/x/arena"
+	buffer "github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer"
+	jsonparser "github.com/superjsf2010/protoc-gen-fastjsonpb/x/jsonparser"
+	proto "google.golang.org/protobuf/proto"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	typepb "google.golang.org/protobuf/types/known/typepb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	sort "sort"
+	sync "sync"
+)
+
+func (x *Item) FastMarshal(buf *buffer.Buffer) {
+	if x == nil {
+		buf.WriteString("{}")
+		return
+	}
+	if buf.NumberOrder() {
+		x.fastMarshalNumberOrder(buf)
+		return
+	}
+	buf.WriteString("{")
+	if !x.IsEmptyName() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("name")
+		buf.WriteString(":")
+		buf.WriteStringWithQuote(x.GetName())
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyId() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("id")
+		buf.WriteString(":")
+		if buf.QuoteInt64() {
+			buf.WriteByte('"')
+			buf.WriteInt64(x.GetId())
+			buf.WriteByte('"')
+		} else {
+			buf.WriteInt64(x.GetId())
+		}
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyKind() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("kind")
+		buf.WriteString(":")
+		if s, ok := Kind_name[int32(x.GetKind())]; ok && !buf.UseEnumNumbers() {
+			buf.WriteStringWithQuote(s)
+		} else {
+			buf.WriteInt32(int32(x.GetKind()))
+		}
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyItems() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("items")
+		buf.WriteString(":")
+		buf.WriteString("[")
+		for i, _ := range x.Items {
+			x.Items[i].FastMarshal(buf)
+			buf.WriteString(",")
+		}
+		buf.FixSymbol()
+		buf.WriteString("]")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyDisplayName() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("display_name")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("displayName")
+			buf.WriteString(":")
+		}
+		buf.WriteStringWithQuote(x.GetDisplayName())
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyChild() {
+		buf.WriteStringWithQuote("child")
+		buf.WriteString(":")
+		x.GetChild().FastMarshal(buf)
+		buf.WriteString(",")
+	} else if buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("child")
+		buf.WriteString(":")
+		buf.WriteString("null")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyCreated() {
+		buf.WriteStringWithQuote("created")
+		buf.WriteString(":")
+		json.MarshalMessage(buf, x.GetCreated())
+		buf.WriteString(",")
+	} else if buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("created")
+		buf.WriteString(":")
+		buf.WriteString("null")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyTags() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("tags")
+		buf.WriteString(":")
+		buf.WriteString("[")
+		for i, _ := range x.Tags {
+			json.MarshalMessage(buf, x.Tags[i])
+			buf.WriteString(",")
+		}
+		buf.FixSymbol()
+		buf.WriteString("]")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyTimeouts() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("timeouts")
+		buf.WriteString(":")
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.Timeouts))
+			for k := range x.Timeouts {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				json.MarshalMessage(buf, x.Timeouts[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Timeouts {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				json.MarshalMessage(buf, x.Timeouts[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptySyntax() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("syntax")
+		buf.WriteString(":")
+		if s, ok := typepb.Syntax_name[int32(x.GetSyntax())]; ok && !buf.UseEnumNumbers() {
+			buf.WriteStringWithQuote(s)
+		} else {
+			buf.WriteInt32(int32(x.GetSyntax()))
+		}
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptySyntaxes() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("syntaxes")
+		buf.WriteString(":")
+		buf.WriteString("[")
+		for i, _ := range x.Syntaxes {
+			if s, ok := typepb.Syntax_name[int32(x.Syntaxes[i])]; ok && !buf.UseEnumNumbers() {
+				buf.WriteStringWithQuote(s)
+			} else {
+				buf.WriteInt32(int32(x.Syntaxes[i]))
+			}
+			buf.WriteString(",")
+		}
+		buf.FixSymbol()
+		buf.WriteString("]")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptySyntaxMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("syntax_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("syntaxMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.SyntaxMap))
+			for k := range x.SyntaxMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if s, ok := typepb.Syntax_name[int32(x.SyntaxMap[k])]; ok && !buf.UseEnumNumbers() {
+					buf.WriteStringWithQuote(s)
+				} else {
+					buf.WriteInt32(int32(x.SyntaxMap[k]))
+				}
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.SyntaxMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if s, ok := typepb.Syntax_name[int32(x.SyntaxMap[k])]; ok && !buf.UseEnumNumbers() {
+					buf.WriteStringWithQuote(s)
+				} else {
+					buf.WriteInt32(int32(x.SyntaxMap[k]))
+				}
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if x.Value != nil {
+		if _, ok := x.GetValue().(*Item_Str); ok {
+			buf.WriteStringWithQuote("str")
+			buf.WriteString(":")
+			buf.WriteStringWithQuote(x.GetStr())
+			buf.WriteString(",")
+
+		} else if _, ok := x.GetValue().(*Item_Item); ok {
+			buf.WriteStringWithQuote("item")
+			buf.WriteString(":")
+			x.GetItem().FastMarshal(buf)
+			buf.WriteString(",")
+
+		} else if _, ok := x.GetValue().(*Item_Num); ok {
+			buf.WriteStringWithQuote("num")
+			buf.WriteString(":")
+			json.MarshalMessage(buf, x.GetNum())
+			buf.WriteString(",")
+		}
+
+	}
+
+	buf.FixSymbol()
+	buf.WriteString("}")
+}
+
+func (x *Item) fastMarshalNumberOrder(buf *buffer.Buffer) {
+	buf.WriteString("{")
+	if !x.IsEmptyName() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("name")
+		buf.WriteString(":")
+		buf.WriteStringWithQuote(x.GetName())
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyId() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("id")
+		buf.WriteString(":")
+		if buf.QuoteInt64() {
+			buf.WriteByte('"')
+			buf.WriteInt64(x.GetId())
+			buf.WriteByte('"')
+		} else {
+			buf.WriteInt64(x.GetId())
+		}
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyKind() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("kind")
+		buf.WriteString(":")
+		if s, ok := Kind_name[int32(x.GetKind())]; ok && !buf.UseEnumNumbers() {
+			buf.WriteStringWithQuote(s)
+		} else {
+			buf.WriteInt32(int32(x.GetKind()))
+		}
+		buf.WriteString(",")
+	}
+
+	if _, ok := x.GetValue().(*Item_Str); ok {
+		buf.WriteStringWithQuote("str")
+		buf.WriteString(":")
+		buf.WriteStringWithQuote(x.GetStr())
+		buf.WriteString(",")
+	}
+
+	if _, ok := x.GetValue().(*Item_Item); ok {
+		buf.WriteStringWithQuote("item")
+		buf.WriteString(":")
+		x.GetItem().FastMarshal(buf)
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyItems() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("items")
+		buf.WriteString(":")
+		buf.WriteString("[")
+		for i, _ := range x.Items {
+			x.Items[i].FastMarshal(buf)
+			buf.WriteString(",")
+		}
+		buf.FixSymbol()
+		buf.WriteString("]")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyDisplayName() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("display_name")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("displayName")
+			buf.WriteString(":")
+		}
+		buf.WriteStringWithQuote(x.GetDisplayName())
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyChild() {
+		buf.WriteStringWithQuote("child")
+		buf.WriteString(":")
+		x.GetChild().FastMarshal(buf)
+		buf.WriteString(",")
+	} else if buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("child")
+		buf.WriteString(":")
+		buf.WriteString("null")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyCreated() {
+		buf.WriteStringWithQuote("created")
+		buf.WriteString(":")
+		json.MarshalMessage(buf, x.GetCreated())
+		buf.WriteString(",")
+	} else if buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("created")
+		buf.WriteString(":")
+		buf.WriteString("null")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyTags() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("tags")
+		buf.WriteString(":")
+		buf.WriteString("[")
+		for i, _ := range x.Tags {
+			json.MarshalMessage(buf, x.Tags[i])
+			buf.WriteString(",")
+		}
+		buf.FixSymbol()
+		buf.WriteString("]")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyTimeouts() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("timeouts")
+		buf.WriteString(":")
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.Timeouts))
+			for k := range x.Timeouts {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				json.MarshalMessage(buf, x.Timeouts[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Timeouts {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				json.MarshalMessage(buf, x.Timeouts[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptySyntax() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("syntax")
+		buf.WriteString(":")
+		if s, ok := typepb.Syntax_name[int32(x.GetSyntax())]; ok && !buf.UseEnumNumbers() {
+			buf.WriteStringWithQuote(s)
+		} else {
+			buf.WriteInt32(int32(x.GetSyntax()))
+		}
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptySyntaxes() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("syntaxes")
+		buf.WriteString(":")
+		buf.WriteString("[")
+		for i, _ := range x.Syntaxes {
+			if s, ok := typepb.Syntax_name[int32(x.Syntaxes[i])]; ok && !buf.UseEnumNumbers() {
+				buf.WriteStringWithQuote(s)
+			} else {
+				buf.WriteInt32(int32(x.Syntaxes[i]))
+			}
+			buf.WriteString(",")
+		}
+		buf.FixSymbol()
+		buf.WriteString("]")
+		buf.WriteString(",")
+	}
+
+	if _, ok := x.GetValue().(*Item_Num); ok {
+		buf.WriteStringWithQuote("num")
+		buf.WriteString(":")
+		json.MarshalMessage(buf, x.GetNum())
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptySyntaxMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("syntax_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("syntaxMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.SyntaxMap))
+			for k := range x.SyntaxMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if s, ok := typepb.Syntax_name[int32(x.SyntaxMap[k])]; ok && !buf.UseEnumNumbers() {
+					buf.WriteStringWithQuote(s)
+				} else {
+					buf.WriteInt32(int32(x.SyntaxMap[k]))
+				}
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.SyntaxMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if s, ok := typepb.Syntax_name[int32(x.SyntaxMap[k])]; ok && !buf.UseEnumNumbers() {
+					buf.WriteStringWithQuote(s)
+				} else {
+					buf.WriteInt32(int32(x.SyntaxMap[k]))
+				}
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	buf.FixSymbol()
+	buf.WriteString("}")
+}
+
+func (x *Item) FastUnmarshal(p *jsonparser.Parser) {
+	if x == nil {
+		panic("type Item is nil")
+	}
+	var seen [1]uint64
+	p.Symbol('{')
+	for !p.IsSymbol('}') {
+		key := p.Key()
+		p.AssertSymbol(':')
+		switch key {
+		case "name":
+			if p.SkipDuplicate(seen[:], 0, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			x.Name = p.Str()
+
+		case "id":
+			if p.SkipDuplicate(seen[:], 1, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			x.Id = p.Int64()
+
+		case "kind":
+			if p.SkipDuplicate(seen[:], 2, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			t, s, i := p.Enum()
+			if t == jsonparser.EnumNumber {
+				x.Kind = Kind(i)
+			} else {
+				x.Kind.SetByStr(s)
+			}
+
+		case "items":
+			if p.SkipDuplicate(seen[:], 3, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('[')
+			arr := x.Items
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
+			for !p.IsSymbol(']') {
+				tmp := ItemArenaNew(p.Arena())
+				tmp.FastUnmarshal(p)
+				arr = ItemArenaAppend(p.Arena(), arr, tmp)
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol(']')
+			x.Items = arr
+
+		case "displayName", "display_name":
+			if p.SkipDuplicate(seen[:], 4, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			x.DisplayName = p.Str()
+
+		case "child":
+			if p.SkipDuplicate(seen[:], 5, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			if x.Child != nil {
+				if !p.Merge() {
+					x.Child.FastReset()
+				}
+			} else {
+				x.Child = ItemArenaNew(p.Arena())
+			}
+			x.Child.FastUnmarshal(p)
+
+		case "created":
+			if p.SkipDuplicate(seen[:], 6, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			if x.Created == nil {
+				x.Created = new(timestamppb.Timestamp)
+			}
+			json.UnmarshalMessage(p, x.Created)
+
+		case "tags":
+			if p.SkipDuplicate(seen[:], 7, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('[')
+			arr := x.Tags
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
+			for !p.IsSymbol(']') {
+				tmp := new(wrapperspb.StringValue)
+				json.UnmarshalMessage(p, tmp)
+				arr = append(arr, tmp)
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol(']')
+			x.Tags = arr
+
+		case "timeouts":
+			if p.SkipDuplicate(seen[:], 8, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('{')
+			m := x.Timeouts
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]*durationpb.Duration)
+			}
+			var keys map[string]struct{}
+			for !p.IsSymbol('}') {
+				key := p.Str()
+				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
+				tmp := new(durationpb.Duration)
+				json.UnmarshalMessage(p, tmp)
+				m[key] = tmp
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol('}')
+			x.Timeouts = m
+
+		case "syntax":
+			if p.SkipDuplicate(seen[:], 9, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			t, s, i := p.Enum()
+			if t == jsonparser.EnumNumber {
+				x.Syntax = typepb.Syntax(i)
+			} else if n, ok := typepb.Syntax_value[s]; ok {
+				x.Syntax = typepb.Syntax(n)
+			} else {
+				panic("enum Syntax value do not match")
+			}
+
+		case "syntaxes":
+			if p.SkipDuplicate(seen[:], 10, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('[')
+			arr := x.Syntaxes
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
+			for !p.IsSymbol(']') {
+				var e typepb.Syntax
+				t, s, i := p.Enum()
+				if t == jsonparser.EnumNumber {
+					e = typepb.Syntax(i)
+				} else if n, ok := typepb.Syntax_value[s]; ok {
+					e = typepb.Syntax(n)
+				} else {
+					panic("enum Syntax value do not match")
+				}
+				arr = append(arr, e)
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol(']')
+			x.Syntaxes = arr
+
+		case "syntaxMap", "syntax_map":
+			if p.SkipDuplicate(seen[:], 11, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('{')
+			m := x.SyntaxMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]typepb.Syntax)
+			}
+			var keys map[string]struct{}
+			for !p.IsSymbol('}') {
+				key := p.Str()
+				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
+				var e typepb.Syntax
+				t, s, i := p.Enum()
+				if t == jsonparser.EnumNumber {
+					e = typepb.Syntax(i)
+				} else if n, ok := typepb.Syntax_value[s]; ok {
+					e = typepb.Syntax(n)
+				} else {
+					panic("enum Syntax value do not match")
+				}
+				m[key] = e
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol('}')
+			x.SyntaxMap = m
+
+		case "str":
+			if p.SkipDuplicate(seen[:], 12, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.Value.(*Item_Str)
+			if !ok {
+				tmp = &Item_Str{}
+			}
+			tmp.Str = p.Str()
+			x.Value = tmp
+		case "item":
+			if p.SkipDuplicate(seen[:], 12, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.Value.(*Item_Item)
+			if !ok {
+				tmp = &Item_Item{}
+			}
+			if tmp.Item != nil {
+				if !p.Merge() {
+					tmp.Item.FastReset()
+				}
+			} else {
+				tmp.Item = ItemArenaNew(p.Arena())
+			}
+			tmp.Item.FastUnmarshal(p)
+			x.Value = tmp
+		case "num":
+			if p.SkipDuplicate(seen[:], 12, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.Value.(*Item_Num)
+			if !ok {
+				tmp = &Item_Num{}
+			}
+			if tmp.Num == nil {
+				tmp.Num = new(wrapperspb.Int64Value)
+			}
+			json.UnmarshalMessage(p, tmp.Num)
+			x.Value = tmp
+		default:
+			p.Unknown(key)
+		}
+		p.AssertSymbol(',')
+	}
+	p.AssertSymbol(',')
+	p.Symbol('}')
+}
+
+var ItemPool sync.Pool
+
+func ItemNew() *Item {
+	if v := ItemPool.Get(); v != nil {
+		return v.(*Item)
+	}
+	return &Item{}
+}
+
+type fastjsonpbItemSlab struct {
+	msgs []Item
+	ptrs []*Item
+}
+
+var fastjsonpbItemSlabID = arena.NewSlabID()
+
+func fastjsonpbItemSlabGet(a *arena.Arena) *fastjsonpbItemSlab {
+	s, _ := a.Slab(fastjsonpbItemSlabID).(*fastjsonpbItemSlab)
+	if s == nil {
+		s = &fastjsonpbItemSlab{}
+		a.SetSlab(fastjsonpbItemSlabID, s)
+	}
+	return s
+}
+
+func ItemArenaNew(a *arena.Arena) *Item {
+	if a == nil {
+		return ItemNew()
+	}
+	s := fastjsonpbItemSlabGet(a)
+	if len(s.msgs) == cap(s.msgs) {
+		s.msgs = make([]Item, 0, arena.SlabLen(cap(s.msgs)))
+	}
+	s.msgs = s.msgs[:len(s.msgs)+1]
+	return &s.msgs[len(s.msgs)-1]
+}
+
+func ItemArenaAppend(a *arena.Arena, arr []*Item, v *Item) []*Item {
+	if a == nil || len(arr) < cap(arr) {
+		return append(arr, v)
+	}
+	s := fastjsonpbItemSlabGet(a)
+	n := arena.GrowLen(cap(arr))
+	if cap(s.ptrs)-len(s.ptrs) < n {
+		s.ptrs = make([]*Item, 0, arena.ChunkLen(cap(s.ptrs), n))
+	}
+	l := len(s.ptrs)
+	s.ptrs = s.ptrs[:l+n]
+	ns := s.ptrs[l : l+len(arr) : l+n]
+	copy(ns, arr)
+	return append(ns, v)
+}
+
+func (x *Item) FastReset() {
+	if x == nil {
+		panic("type Item is nil")
+	}
+	x.Name = ""
+	x.Id = 0
+	x.Kind = 0
+	if len(x.Items) > 0 {
+		x.Items = nil
+	}
+	x.DisplayName = ""
+	x.Child = nil
+	x.Created = nil
+	if len(x.Tags) > 0 {
+		x.Tags = nil
+	}
+	if len(x.Timeouts) > 0 {
+		x.Timeouts = nil
+	}
+	x.Syntax = 0
+	if len(x.Syntaxes) > 0 {
+		x.Syntaxes = nil
+	}
+	if len(x.SyntaxMap) > 0 {
+		x.SyntaxMap = nil
+	}
+	if x.Value != nil {
+		x.Value = nil
+	}
+}
+
+func (x *Item) Destructor() {
+	if x == nil {
+		panic("type Item is nil")
+	}
+	x.Name = ""
+	x.Id = 0
+	x.Kind = 0
+	for i, _ := range x.Items {
+		x.Items[i].Destructor()
+		x.Items[i] = nil
+	}
+	x.Items = x.Items[:0]
+	x.DisplayName = ""
+	if x.Child != nil {
+		x.Child.Destructor()
+		x.Child = nil
+	}
+	x.Created = nil
+	for i, _ := range x.Tags {
+		x.Tags[i] = nil
+	}
+	x.Tags = x.Tags[:0]
+	for k, _ := range x.Timeouts {
+		delete(x.Timeouts, k)
+	}
+	x.Syntax = 0
+	x.Syntaxes = x.Syntaxes[:0]
+	for k, _ := range x.SyntaxMap {
+		delete(x.SyntaxMap, k)
+	}
+	if x.Value != nil {
+		if _, ok := x.GetValue().(*Item_Item); ok {
+			x.GetItem().Destructor()
+		}
+		x.Value = nil
+	}
+	ItemPool.Put(x)
+}
+
+func (x *Item) IsEmptyName() bool {
+	return x.GetName() == ""
+}
+
+func (x *Item) IsEmptyId() bool {
+	return x.GetId() == 0
+}
+
+func (x *Item) IsEmptyKind() bool {
+	return int32(x.GetKind()) == 0
+}
+
+func (x *Item) IsEmptyItems() bool {
+	return len(x.GetItems()) == 0
+}
+
+func (x *Item) IsEmptyDisplayName() bool {
+	return x.GetDisplayName() == ""
+}
+
+func (x *Item) IsEmptyChild() bool {
+	return x.GetChild() == nil
+}
+
+func (x *Item) IsEmptyCreated() bool {
+	return x.GetCreated() == nil
+}
+
+func (x *Item) IsEmptyTags() bool {
+	return len(x.GetTags()) == 0
+}
+
+func (x *Item) IsEmptyTimeouts() bool {
+	return len(x.GetTimeouts()) == 0
+}
+
+func (x *Item) IsEmptySyntax() bool {
+	return int32(x.GetSyntax()) == 0
+}
+
+func (x *Item) IsEmptySyntaxes() bool {
+	return len(x.GetSyntaxes()) == 0
+}
+
+func (x *Item) IsEmptySyntaxMap() bool {
+	return len(x.GetSyntaxMap()) == 0
+}
+
+func (x *Item) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x)
+}
+
+func (x *Item) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, x)
+}
+
+func (x *Item) MarshalJSONPB(m *jsonpb.Marshaler) ([]byte, error) {
+	return jsonpbcompat.MarshalJSONPB(m, x)
+}
+
+func (x *Item) UnmarshalJSONPB(u *jsonpb.Unmarshaler, b []byte) error {
+	return jsonpbcompat.UnmarshalJSONPB(u, b, x)
+}
+
+func (x Kind) Get(i int32) Kind {
+	if _, ok := Kind_name[i]; ok {
+		return Kind(i)
+	}
+	panic("enum Kindvalue do not match")
+}
+
+func (x Kind) GetByStr(s string) Kind {
+	if i, ok := Kind_value[s]; ok {
+		return Kind(i)
+	}
+	panic("enum Kindvalue do not match")
+}
+
+// Set 设置为数值i，i不是定义过的值时panic
+func (x *Kind) Set(i int32) {
+	if _, ok := Kind_name[i]; ok {
+		*x = Kind(i)
+		return
+	}
+	panic("enum Kindvalue do not match")
+}
+
+// SetByStr 设置为名字s对应的值，s不是定义过的名字时panic
+func (x *Kind) SetByStr(s string) {
+	if i, ok := Kind_value[s]; ok {
+		*x = Kind(i)
+		return
+	}
+	panic("enum Kindvalue do not match")
+}
+
+func init() {
+	json.RegisterType("embed.Item", func() proto.Message { return ItemNew() }, func(m proto.Message) { m.(*Item).Destructor() })
+}
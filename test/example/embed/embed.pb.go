@@ -0,0 +1,422 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.21.2
+// source: embed/embed.proto
+
+package embed
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	typepb "google.golang.org/protobuf/types/known/typepb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 以json_methods=true,jsonpb_methods=true编译，生成MarshalJSON、UnmarshalJSON、MarshalJSONPB、UnmarshalJSONPB
+type Kind int32
+
+const (
+	Kind_KIND_UNKNOWN Kind = 0
+	Kind_KIND_A       Kind = 1
+)
+
+// Enum value maps for Kind.
+var (
+	Kind_name = map[int32]string{
+		0: "KIND_UNKNOWN",
+		1: "KIND_A",
+	}
+	Kind_value = map[string]int32{
+		"KIND_UNKNOWN": 0,
+		"KIND_A":       1,
+	}
+)
+
+func (x Kind) Enum() *Kind {
+	p := new(Kind)
+	*p = x
+	return p
+}
+
+func (x Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_embed_embed_proto_enumTypes[0].Descriptor()
+}
+
+func (Kind) Type() protoreflect.EnumType {
+	return &file_embed_embed_proto_enumTypes[0]
+}
+
+func (x Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Kind.Descriptor instead.
+func (Kind) EnumDescriptor() ([]byte, []int) {
+	return file_embed_embed_proto_rawDescGZIP(), []int{0}
+}
+
+type Item struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Id   int64  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Kind Kind   `protobuf:"varint,3,opt,name=kind,proto3,enum=embed.Kind" json:"kind,omitempty"`
+	// Types that are assignable to Value:
+	//	*Item_Str
+	//	*Item_Item
+	//	*Item_Num
+	Value       isItem_Value                    `protobuf_oneof:"value"`
+	Items       []*Item                         `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	DisplayName string                          `protobuf:"bytes,7,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Child       *Item                           `protobuf:"bytes,8,opt,name=child,proto3" json:"child,omitempty"`
+	Created     *timestamppb.Timestamp          `protobuf:"bytes,9,opt,name=created,proto3" json:"created,omitempty"`
+	Tags        []*wrapperspb.StringValue       `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	Timeouts    map[string]*durationpb.Duration `protobuf:"bytes,11,rep,name=timeouts,proto3" json:"timeouts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Syntax      typepb.Syntax                   `protobuf:"varint,12,opt,name=syntax,proto3,enum=google.protobuf.Syntax" json:"syntax,omitempty"`
+	Syntaxes    []typepb.Syntax                 `protobuf:"varint,13,rep,packed,name=syntaxes,proto3,enum=google.protobuf.Syntax" json:"syntaxes,omitempty"`
+	SyntaxMap   map[string]typepb.Syntax        `protobuf:"bytes,15,rep,name=syntax_map,json=syntaxMap,proto3" json:"syntax_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3,enum=google.protobuf.Syntax"`
+}
+
+func (x *Item) Reset() {
+	*x = Item{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_embed_embed_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Item) ProtoMessage() {}
+
+func (x *Item) ProtoReflect() protoreflect.Message {
+	mi := &file_embed_embed_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Item.ProtoReflect.Descriptor instead.
+func (*Item) Descriptor() ([]byte, []int) {
+	return file_embed_embed_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Item) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Item) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Item) GetKind() Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Kind_KIND_UNKNOWN
+}
+
+func (m *Item) GetValue() isItem_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *Item) GetStr() string {
+	if x, ok := x.GetValue().(*Item_Str); ok {
+		return x.Str
+	}
+	return ""
+}
+
+func (x *Item) GetItem() *Item {
+	if x, ok := x.GetValue().(*Item_Item); ok {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *Item) GetNum() *wrapperspb.Int64Value {
+	if x, ok := x.GetValue().(*Item_Num); ok {
+		return x.Num
+	}
+	return nil
+}
+
+func (x *Item) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Item) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Item) GetChild() *Item {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+func (x *Item) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *Item) GetTags() []*wrapperspb.StringValue {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Item) GetTimeouts() map[string]*durationpb.Duration {
+	if x != nil {
+		return x.Timeouts
+	}
+	return nil
+}
+
+func (x *Item) GetSyntax() typepb.Syntax {
+	if x != nil {
+		return x.Syntax
+	}
+	return typepb.Syntax(0)
+}
+
+func (x *Item) GetSyntaxes() []typepb.Syntax {
+	if x != nil {
+		return x.Syntaxes
+	}
+	return nil
+}
+
+func (x *Item) GetSyntaxMap() map[string]typepb.Syntax {
+	if x != nil {
+		return x.SyntaxMap
+	}
+	return nil
+}
+
+type isItem_Value interface {
+	isItem_Value()
+}
+
+type Item_Str struct {
+	Str string `protobuf:"bytes,4,opt,name=str,proto3,oneof"`
+}
+
+type Item_Item struct {
+	Item *Item `protobuf:"bytes,5,opt,name=item,proto3,oneof"`
+}
+
+type Item_Num struct {
+	Num *wrapperspb.Int64Value `protobuf:"bytes,14,opt,name=num,proto3,oneof"`
+}
+
+func (*Item_Str) isItem_Value() {}
+
+func (*Item_Item) isItem_Value() {}
+
+func (*Item_Num) isItem_Value() {}
+
+var File_embed_embed_proto protoreflect.FileDescriptor
+
+var file_embed_embed_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x2f, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x05, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1a, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x79, 0x70,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x94, 0x06, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x1f, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x52,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x03, 0x73, 0x74, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x03, 0x73, 0x74, 0x72, 0x12, 0x21, 0x0a, 0x04, 0x69, 0x74, 0x65,
+	0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x2e,
+	0x49, 0x74, 0x65, 0x6d, 0x48, 0x00, 0x52, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x12, 0x2f, 0x0a, 0x03,
+	0x6e, 0x75, 0x6d, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x49, 0x6e, 0x74, 0x36,
+	0x34, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x48, 0x00, 0x52, 0x03, 0x6e, 0x75, 0x6d, 0x12, 0x21, 0x0a,
+	0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x65,
+	0x6d, 0x62, 0x65, 0x64, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73,
+	0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52,
+	0x05, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x04,
+	0x74, 0x61, 0x67, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x35,
+	0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x79, 0x6e, 0x74, 0x61, 0x78, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x79, 0x6e, 0x74, 0x61, 0x78, 0x52, 0x06,
+	0x73, 0x79, 0x6e, 0x74, 0x61, 0x78, 0x12, 0x33, 0x0a, 0x08, 0x73, 0x79, 0x6e, 0x74, 0x61, 0x78,
+	0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x79, 0x6e, 0x74, 0x61,
+	0x78, 0x52, 0x08, 0x73, 0x79, 0x6e, 0x74, 0x61, 0x78, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x73,
+	0x79, 0x6e, 0x74, 0x61, 0x78, 0x5f, 0x6d, 0x61, 0x70, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x2e, 0x53, 0x79, 0x6e,
+	0x74, 0x61, 0x78, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x73, 0x79, 0x6e,
+	0x74, 0x61, 0x78, 0x4d, 0x61, 0x70, 0x1a, 0x56, 0x0a, 0x0d, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x55,
+	0x0a, 0x0e, 0x53, 0x79, 0x6e, 0x74, 0x61, 0x78, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x2d, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x53, 0x79, 0x6e, 0x74, 0x61, 0x78, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2a, 0x24,
+	0x0a, 0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x10, 0x0a, 0x0c, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x4b, 0x49, 0x4e, 0x44,
+	0x5f, 0x41, 0x10, 0x01, 0x42, 0x14, 0x5a, 0x12, 0x74, 0x65, 0x73, 0x74, 0x2f, 0x65, 0x78, 0x61,
+	0x6d, 0x70, 0x6c, 0x65, 0x2f, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_embed_embed_proto_rawDescOnce sync.Once
+	file_embed_embed_proto_rawDescData = file_embed_embed_proto_rawDesc
+)
+
+func file_embed_embed_proto_rawDescGZIP() []byte {
+	file_embed_embed_proto_rawDescOnce.Do(func() {
+		file_embed_embed_proto_rawDescData = protoimpl.X.CompressGZIP(file_embed_embed_proto_rawDescData)
+	})
+	return file_embed_embed_proto_rawDescData
+}
+
+var file_embed_embed_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_embed_embed_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_embed_embed_proto_goTypes = []interface{}{
+	(Kind)(0),                      // 0: embed.Kind
+	(*Item)(nil),                   // 1: embed.Item
+	nil,                            // 2: embed.Item.TimeoutsEntry
+	nil,                            // 3: embed.Item.SyntaxMapEntry
+	(*wrapperspb.Int64Value)(nil),  // 4: google.protobuf.Int64Value
+	(*timestamppb.Timestamp)(nil),  // 5: google.protobuf.Timestamp
+	(*wrapperspb.StringValue)(nil), // 6: google.protobuf.StringValue
+	(typepb.Syntax)(0),             // 7: google.protobuf.Syntax
+	(*durationpb.Duration)(nil),    // 8: google.protobuf.Duration
+}
+var file_embed_embed_proto_depIdxs = []int32{
+	0,  // 0: embed.Item.kind:type_name -> embed.Kind
+	1,  // 1: embed.Item.item:type_name -> embed.Item
+	4,  // 2: embed.Item.num:type_name -> google.protobuf.Int64Value
+	1,  // 3: embed.Item.items:type_name -> embed.Item
+	1,  // 4: embed.Item.child:type_name -> embed.Item
+	5,  // 5: embed.Item.created:type_name -> google.protobuf.Timestamp
+	6,  // 6: embed.Item.tags:type_name -> google.protobuf.StringValue
+	2,  // 7: embed.Item.timeouts:type_name -> embed.Item.TimeoutsEntry
+	7,  // 8: embed.Item.syntax:type_name -> google.protobuf.Syntax
+	7,  // 9: embed.Item.syntaxes:type_name -> google.protobuf.Syntax
+	3,  // 10: embed.Item.syntax_map:type_name -> embed.Item.SyntaxMapEntry
+	8,  // 11: embed.Item.TimeoutsEntry.value:type_name -> google.protobuf.Duration
+	7,  // 12: embed.Item.SyntaxMapEntry.value:type_name -> google.protobuf.Syntax
+	13, // [13:13] is the sub-list for method output_type
+	13, // [13:13] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_embed_embed_proto_init() }
+func file_embed_embed_proto_init() {
+	if File_embed_embed_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_embed_embed_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Item); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_embed_embed_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*Item_Str)(nil),
+		(*Item_Item)(nil),
+		(*Item_Num)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_embed_embed_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_embed_embed_proto_goTypes,
+		DependencyIndexes: file_embed_embed_proto_depIdxs,
+		EnumInfos:         file_embed_embed_proto_enumTypes,
+		MessageInfos:      file_embed_embed_proto_msgTypes,
+	}.Build()
+	File_embed_embed_proto = out.File
+	file_embed_embed_proto_rawDesc = nil
+	file_embed_embed_proto_goTypes = nil
+	file_embed_embed_proto_depIdxs = nil
+}
@@ -4,82 +4,104 @@
 package example
 
 import (
+	json "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/json"
+	arena "github.com/superjsf2010/protoc-gen-fastjsonpb/x/arena"
 	buffer "github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer"
 	jsonparser "github.com/superjsf2010/protoc-gen-fastjsonpb/x/jsonparser"
+	proto "google.golang.org/protobuf/proto"
+	sort "sort"
 	sync "sync"
 )
 
 func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 	if x == nil {
 		buf.WriteString("{}")
+		return
 	}
 	buf.WriteString("{")
-	if !x.IsEmptyBol() {
+	if !x.IsEmptyBol() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("bol")
 		buf.WriteString(":")
 		buf.WriteBool(x.GetBol())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyStr() {
+	if !x.IsEmptyStr() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("str")
 		buf.WriteString(":")
 		buf.WriteStringWithQuote(x.GetStr())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn32() {
+	if !x.IsEmptyIn32() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("in32")
 		buf.WriteString(":")
 		buf.WriteInt32(x.GetIn32())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn64() {
+	if !x.IsEmptyIn64() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("in64")
 		buf.WriteString(":")
-		buf.WriteInt64(x.GetIn64())
+		if buf.QuoteInt64() {
+			buf.WriteByte('"')
+			buf.WriteInt64(x.GetIn64())
+			buf.WriteByte('"')
+		} else {
+			buf.WriteInt64(x.GetIn64())
+		}
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin32() {
+	if !x.IsEmptyUin32() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("uin32")
 		buf.WriteString(":")
 		buf.WriteUint32(x.GetUin32())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin64() {
+	if !x.IsEmptyUin64() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("uin64")
 		buf.WriteString(":")
-		buf.WriteUint64(x.GetUin64())
+		if buf.QuoteInt64() {
+			buf.WriteByte('"')
+			buf.WriteUint64(x.GetUin64())
+			buf.WriteByte('"')
+		} else {
+			buf.WriteUint64(x.GetUin64())
+		}
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt32() {
+	if !x.IsEmptyFlt32() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("flt32")
 		buf.WriteString(":")
 		buf.WriteFloat32(x.GetFlt32())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt64() {
+	if !x.IsEmptyFlt64() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("flt64")
 		buf.WriteString(":")
 		buf.WriteFloat64(x.GetFlt64())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyByts() {
+	if !x.IsEmptyByts() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("byts")
 		buf.WriteString(":")
 		buf.WriteBytes(x.GetByts())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyBolArr() {
-		buf.WriteStringWithQuote("bolArr")
-		buf.WriteString(":")
+	if !x.IsEmptyBolArr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("bol_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("bolArr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.BolArr {
 			buf.WriteBool(x.BolArr[i])
@@ -90,9 +112,14 @@ func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyStrArr() {
-		buf.WriteStringWithQuote("strArr")
-		buf.WriteString(":")
+	if !x.IsEmptyStrArr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("str_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("strArr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.StrArr {
 			buf.WriteStringWithQuote(x.StrArr[i])
@@ -103,9 +130,14 @@ func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn32Arr() {
-		buf.WriteStringWithQuote("in32Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyIn32Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in32_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("in32Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.In32Arr {
 			buf.WriteInt32(x.In32Arr[i])
@@ -116,12 +148,23 @@ func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn64Arr() {
-		buf.WriteStringWithQuote("in64Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyIn64Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in64_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("in64Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.In64Arr {
-			buf.WriteInt64(x.In64Arr[i])
+			if buf.QuoteInt64() {
+				buf.WriteByte('"')
+				buf.WriteInt64(x.In64Arr[i])
+				buf.WriteByte('"')
+			} else {
+				buf.WriteInt64(x.In64Arr[i])
+			}
 			buf.WriteString(",")
 		}
 		buf.FixSymbol()
@@ -129,9 +172,14 @@ func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin32Arr() {
-		buf.WriteStringWithQuote("uin32Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyUin32Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin32_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("uin32Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.Uin32Arr {
 			buf.WriteUint32(x.Uin32Arr[i])
@@ -142,12 +190,23 @@ func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin64Arr() {
-		buf.WriteStringWithQuote("uin64Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyUin64Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin64_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("uin64Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.Uin64Arr {
-			buf.WriteUint64(x.Uin64Arr[i])
+			if buf.QuoteInt64() {
+				buf.WriteByte('"')
+				buf.WriteUint64(x.Uin64Arr[i])
+				buf.WriteByte('"')
+			} else {
+				buf.WriteUint64(x.Uin64Arr[i])
+			}
 			buf.WriteString(",")
 		}
 		buf.FixSymbol()
@@ -155,9 +214,14 @@ func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt32Arr() {
-		buf.WriteStringWithQuote("flt32Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyFlt32Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("flt32_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("flt32Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.Flt32Arr {
 			buf.WriteFloat32(x.Flt32Arr[i])
@@ -168,9 +232,14 @@ func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt64Arr() {
-		buf.WriteStringWithQuote("flt64Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyFlt64Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("flt64_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("flt64Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.Flt64Arr {
 			buf.WriteFloat64(x.Flt64Arr[i])
@@ -181,9 +250,14 @@ func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyBytsArr() {
-		buf.WriteStringWithQuote("bytsArr")
-		buf.WriteString(":")
+	if !x.IsEmptyBytsArr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("byts_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("bytsArr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.BytsArr {
 			buf.WriteBytes(x.BytsArr[i])
@@ -194,135 +268,330 @@ func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyBolMap() {
-		buf.WriteStringWithQuote("bolMap")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.BolMap {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyBolMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("bol_map")
 			buf.WriteString(":")
-			buf.WriteBool(x.BolMap[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("bolMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.BolMap))
+			for k := range x.BolMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteBool(x.BolMap[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.BolMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteBool(x.BolMap[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyStringMap() {
-		buf.WriteStringWithQuote("stringMap")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.StringMap {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyStringMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("string_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("stringMap")
 			buf.WriteString(":")
-			buf.WriteStringWithQuote(x.StringMap[k])
-			buf.WriteString(",")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.StringMap))
+			for k := range x.StringMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.StringMap[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.StringMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.StringMap[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn32Map() {
-		buf.WriteStringWithQuote("in32Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.In32Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyIn32Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in32_map")
 			buf.WriteString(":")
-			buf.WriteInt32(x.In32Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("in32Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.In32Map))
+			for k := range x.In32Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteInt32(x.In32Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.In32Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteInt32(x.In32Map[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn64Map() {
-		buf.WriteStringWithQuote("in64Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.In64Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyIn64Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in64_map")
 			buf.WriteString(":")
-			buf.WriteInt64(x.In64Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("in64Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.In64Map))
+			for k := range x.In64Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if buf.QuoteInt64() {
+					buf.WriteByte('"')
+					buf.WriteInt64(x.In64Map[k])
+					buf.WriteByte('"')
+				} else {
+					buf.WriteInt64(x.In64Map[k])
+				}
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.In64Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if buf.QuoteInt64() {
+					buf.WriteByte('"')
+					buf.WriteInt64(x.In64Map[k])
+					buf.WriteByte('"')
+				} else {
+					buf.WriteInt64(x.In64Map[k])
+				}
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin32Map() {
-		buf.WriteStringWithQuote("uin32Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.Uin32Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyUin32Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin32_map")
 			buf.WriteString(":")
-			buf.WriteUint32(x.Uin32Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("uin32Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.Uin32Map))
+			for k := range x.Uin32Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteUint32(x.Uin32Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Uin32Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteUint32(x.Uin32Map[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin64Map() {
-		buf.WriteStringWithQuote("uin64Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.Uin64Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyUin64Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin64_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("uin64Map")
 			buf.WriteString(":")
-			buf.WriteUint64(x.Uin64Map[k])
-			buf.WriteString(",")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.Uin64Map))
+			for k := range x.Uin64Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if buf.QuoteInt64() {
+					buf.WriteByte('"')
+					buf.WriteUint64(x.Uin64Map[k])
+					buf.WriteByte('"')
+				} else {
+					buf.WriteUint64(x.Uin64Map[k])
+				}
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Uin64Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if buf.QuoteInt64() {
+					buf.WriteByte('"')
+					buf.WriteUint64(x.Uin64Map[k])
+					buf.WriteByte('"')
+				} else {
+					buf.WriteUint64(x.Uin64Map[k])
+				}
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt32Map() {
-		buf.WriteStringWithQuote("flt32Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.Flt32Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyFlt32Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("flt32_map")
 			buf.WriteString(":")
-			buf.WriteFloat32(x.Flt32Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("flt32Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.Flt32Map))
+			for k := range x.Flt32Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteFloat32(x.Flt32Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Flt32Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteFloat32(x.Flt32Map[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt64Map() {
-		buf.WriteStringWithQuote("flt64Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.Flt64Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyFlt64Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("flt64_map")
 			buf.WriteString(":")
-			buf.WriteFloat64(x.Flt64Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("flt64Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.Flt64Map))
+			for k := range x.Flt64Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteFloat64(x.Flt64Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Flt64Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteFloat64(x.Flt64Map[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyBytsMap() {
-		buf.WriteStringWithQuote("bytsMap")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.BytsMap {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyBytsMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("byts_map")
 			buf.WriteString(":")
-			buf.WriteBytes(x.BytsMap[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("bytsMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.BytsMap))
+			for k := range x.BytsMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteBytes(x.BytsMap[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.BytsMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteBytes(x.BytsMap[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
@@ -331,14 +600,20 @@ func (x *Msg) FastMarshal(buf *buffer.Buffer) {
 
 	if x.TestOneof != nil {
 		if _, ok := x.GetTestOneof().(*Msg_OneofBol); ok {
-			buf.WriteStringWithQuote("oneofBol")
-			buf.WriteString(":")
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_bol")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofBol")
+				buf.WriteString(":")
+			}
 			buf.WriteBool(x.GetOneofBol())
 			buf.WriteString(",")
 		}
 
 	}
 
+	fastjsonpbMsgUnknown.Marshal(buf, x.unknownFields)
 	buf.FixSymbol()
 	buf.WriteString("}")
 }
@@ -347,143 +622,299 @@ func (x *Msg) FastUnmarshal(p *jsonparser.Parser) {
 	if x == nil {
 		panic("type Msg is nil")
 	}
+	var seen [1]uint64
 	p.Symbol('{')
 	for !p.IsSymbol('}') {
-		key := p.Str()
+		key := p.Key()
 		p.AssertSymbol(':')
 		switch key {
 		case "bol":
+			if p.SkipDuplicate(seen[:], 0, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Bol = p.Bol()
 
 		case "str":
+			if p.SkipDuplicate(seen[:], 1, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Str = p.Str()
 
 		case "in32":
+			if p.SkipDuplicate(seen[:], 2, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.In32 = p.Int32()
 
 		case "in64":
+			if p.SkipDuplicate(seen[:], 3, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.In64 = p.Int64()
 
 		case "uin32":
+			if p.SkipDuplicate(seen[:], 4, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Uin32 = p.Uint32()
 
 		case "uin64":
+			if p.SkipDuplicate(seen[:], 5, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Uin64 = p.Uint64()
 
 		case "flt32":
+			if p.SkipDuplicate(seen[:], 6, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Flt32 = p.Float32()
 
 		case "flt64":
+			if p.SkipDuplicate(seen[:], 7, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Flt64 = p.Float64()
 
 		case "byts":
+			if p.SkipDuplicate(seen[:], 8, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Byts = p.Bytes()
 
-		case "bolArr":
+		case "bolArr", "bol_arr":
+			if p.SkipDuplicate(seen[:], 9, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]bool, 0)
+			arr := x.BolArr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Bol())
+				arr = p.Arena().AppendBool(arr, p.Bol())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.BolArr = arr
 
-		case "strArr":
+		case "strArr", "str_arr":
+			if p.SkipDuplicate(seen[:], 10, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]string, 0)
+			arr := x.StrArr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Str())
+				arr = p.Arena().AppendString(arr, p.Str())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.StrArr = arr
 
-		case "in32Arr":
+		case "in32Arr", "in32_arr":
+			if p.SkipDuplicate(seen[:], 11, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]int32, 0)
+			arr := x.In32Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Int32())
+				arr = p.Arena().AppendInt32(arr, p.Int32())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.In32Arr = arr
 
-		case "in64Arr":
+		case "in64Arr", "in64_arr":
+			if p.SkipDuplicate(seen[:], 12, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]int64, 0)
+			arr := x.In64Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Int64())
+				arr = p.Arena().AppendInt64(arr, p.Int64())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.In64Arr = arr
 
-		case "uin32Arr":
+		case "uin32Arr", "uin32_arr":
+			if p.SkipDuplicate(seen[:], 13, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]uint32, 0)
+			arr := x.Uin32Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Uint32())
+				arr = p.Arena().AppendUint32(arr, p.Uint32())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.Uin32Arr = arr
 
-		case "uin64Arr":
+		case "uin64Arr", "uin64_arr":
+			if p.SkipDuplicate(seen[:], 14, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]uint64, 0)
+			arr := x.Uin64Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Uint64())
+				arr = p.Arena().AppendUint64(arr, p.Uint64())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.Uin64Arr = arr
 
-		case "flt32Arr":
+		case "flt32Arr", "flt32_arr":
+			if p.SkipDuplicate(seen[:], 15, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]float32, 0)
+			arr := x.Flt32Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Float32())
+				arr = p.Arena().AppendFloat32(arr, p.Float32())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.Flt32Arr = arr
 
-		case "flt64Arr":
+		case "flt64Arr", "flt64_arr":
+			if p.SkipDuplicate(seen[:], 16, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]float64, 0)
+			arr := x.Flt64Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Float64())
+				arr = p.Arena().AppendFloat64(arr, p.Float64())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.Flt64Arr = arr
 
-		case "bytsArr":
+		case "bytsArr", "byts_arr":
+			if p.SkipDuplicate(seen[:], 17, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([][]byte, 0)
+			arr := x.BytsArr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Bytes())
+				arr = p.Arena().AppendBytes(arr, p.Bytes())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.BytsArr = arr
 
-		case "bolMap":
+		case "bolMap", "bol_map":
+			if p.SkipDuplicate(seen[:], 18, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]bool)
+			m := x.BolMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]bool)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Bol()
 				p.AssertSymbol(',')
 			}
@@ -491,12 +922,32 @@ func (x *Msg) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.BolMap = m
 
-		case "stringMap":
+		case "stringMap", "string_map":
+			if p.SkipDuplicate(seen[:], 19, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]string)
+			m := x.StringMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]string)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Str()
 				p.AssertSymbol(',')
 			}
@@ -504,12 +955,32 @@ func (x *Msg) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.StringMap = m
 
-		case "in32Map":
+		case "in32Map", "in32_map":
+			if p.SkipDuplicate(seen[:], 20, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]int32)
+			m := x.In32Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]int32)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Int32()
 				p.AssertSymbol(',')
 			}
@@ -517,12 +988,32 @@ func (x *Msg) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.In32Map = m
 
-		case "in64Map":
+		case "in64Map", "in64_map":
+			if p.SkipDuplicate(seen[:], 21, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]int64)
+			m := x.In64Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]int64)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Int64()
 				p.AssertSymbol(',')
 			}
@@ -530,12 +1021,32 @@ func (x *Msg) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.In64Map = m
 
-		case "uin32Map":
+		case "uin32Map", "uin32_map":
+			if p.SkipDuplicate(seen[:], 22, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]uint32)
+			m := x.Uin32Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]uint32)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Uint32()
 				p.AssertSymbol(',')
 			}
@@ -543,12 +1054,32 @@ func (x *Msg) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.Uin32Map = m
 
-		case "uin64Map":
+		case "uin64Map", "uin64_map":
+			if p.SkipDuplicate(seen[:], 23, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]uint64)
+			m := x.Uin64Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]uint64)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Uint64()
 				p.AssertSymbol(',')
 			}
@@ -556,12 +1087,32 @@ func (x *Msg) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.Uin64Map = m
 
-		case "flt32Map":
+		case "flt32Map", "flt32_map":
+			if p.SkipDuplicate(seen[:], 24, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]float32)
+			m := x.Flt32Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]float32)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Float32()
 				p.AssertSymbol(',')
 			}
@@ -569,12 +1120,32 @@ func (x *Msg) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.Flt32Map = m
 
-		case "flt64Map":
+		case "flt64Map", "flt64_map":
+			if p.SkipDuplicate(seen[:], 25, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]float64)
+			m := x.Flt64Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]float64)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Float64()
 				p.AssertSymbol(',')
 			}
@@ -582,12 +1153,32 @@ func (x *Msg) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.Flt64Map = m
 
-		case "bytsMap":
+		case "bytsMap", "byts_map":
+			if p.SkipDuplicate(seen[:], 26, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string][]byte)
+			m := x.BytsMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string][]byte)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Bytes()
 				p.AssertSymbol(',')
 			}
@@ -595,12 +1186,21 @@ func (x *Msg) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.BytsMap = m
 
-		case "oneofBol":
-			tmp := &Msg_OneofBol{}
+		case "oneofBol", "oneof_bol":
+			if p.SkipDuplicate(seen[:], 27, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Msg_OneofBol)
+			if !ok {
+				tmp = &Msg_OneofBol{}
+			}
 			tmp.OneofBol = p.Bol()
 			x.TestOneof = tmp
 		default:
-			p.PassParse()
+			fastjsonpbMsgUnknown.Append(&x.unknownFields, key, p.UnknownValue(key))
 		}
 		p.AssertSymbol(',')
 	}
@@ -616,7 +1216,54 @@ func MsgNew() *Msg {
 	}
 	return &Msg{}
 }
-func (x *Msg) Destructor() {
+
+var fastjsonpbMsgUnknown jsonparser.UnknownTable
+
+type fastjsonpbMsgSlab struct {
+	msgs []Msg
+	ptrs []*Msg
+}
+
+var fastjsonpbMsgSlabID = arena.NewSlabID()
+
+func fastjsonpbMsgSlabGet(a *arena.Arena) *fastjsonpbMsgSlab {
+	s, _ := a.Slab(fastjsonpbMsgSlabID).(*fastjsonpbMsgSlab)
+	if s == nil {
+		s = &fastjsonpbMsgSlab{}
+		a.SetSlab(fastjsonpbMsgSlabID, s)
+	}
+	return s
+}
+
+func MsgArenaNew(a *arena.Arena) *Msg {
+	if a == nil {
+		return MsgNew()
+	}
+	s := fastjsonpbMsgSlabGet(a)
+	if len(s.msgs) == cap(s.msgs) {
+		s.msgs = make([]Msg, 0, arena.SlabLen(cap(s.msgs)))
+	}
+	s.msgs = s.msgs[:len(s.msgs)+1]
+	return &s.msgs[len(s.msgs)-1]
+}
+
+func MsgArenaAppend(a *arena.Arena, arr []*Msg, v *Msg) []*Msg {
+	if a == nil || len(arr) < cap(arr) {
+		return append(arr, v)
+	}
+	s := fastjsonpbMsgSlabGet(a)
+	n := arena.GrowLen(cap(arr))
+	if cap(s.ptrs)-len(s.ptrs) < n {
+		s.ptrs = make([]*Msg, 0, arena.ChunkLen(cap(s.ptrs), n))
+	}
+	l := len(s.ptrs)
+	s.ptrs = s.ptrs[:l+n]
+	ns := s.ptrs[l : l+len(arr) : l+n]
+	copy(ns, arr)
+	return append(ns, v)
+}
+
+func (x *Msg) FastReset() {
 	if x == nil {
 		panic("type Msg is nil")
 	}
@@ -629,31 +1276,129 @@ func (x *Msg) Destructor() {
 	x.Flt32 = 0
 	x.Flt64 = 0
 	x.Byts = nil
-	x.BolArr = nil
-	x.StrArr = nil
-	x.In32Arr = nil
-	x.In64Arr = nil
-	x.Uin32Arr = nil
-	x.Uin64Arr = nil
-	x.Flt32Arr = nil
-	x.Flt64Arr = nil
-	x.BytsArr = nil
-	x.BolMap = nil
-	x.StringMap = nil
-	x.In32Map = nil
-	x.In64Map = nil
-	x.Uin32Map = nil
-	x.Uin64Map = nil
-	x.Flt32Map = nil
-	x.Flt64Map = nil
-	x.BytsMap = nil
+	if len(x.BolArr) > 0 {
+		x.BolArr = nil
+	}
+	if len(x.StrArr) > 0 {
+		x.StrArr = nil
+	}
+	if len(x.In32Arr) > 0 {
+		x.In32Arr = nil
+	}
+	if len(x.In64Arr) > 0 {
+		x.In64Arr = nil
+	}
+	if len(x.Uin32Arr) > 0 {
+		x.Uin32Arr = nil
+	}
+	if len(x.Uin64Arr) > 0 {
+		x.Uin64Arr = nil
+	}
+	if len(x.Flt32Arr) > 0 {
+		x.Flt32Arr = nil
+	}
+	if len(x.Flt64Arr) > 0 {
+		x.Flt64Arr = nil
+	}
+	if len(x.BytsArr) > 0 {
+		x.BytsArr = nil
+	}
+	if len(x.BolMap) > 0 {
+		x.BolMap = nil
+	}
+	if len(x.StringMap) > 0 {
+		x.StringMap = nil
+	}
+	if len(x.In32Map) > 0 {
+		x.In32Map = nil
+	}
+	if len(x.In64Map) > 0 {
+		x.In64Map = nil
+	}
+	if len(x.Uin32Map) > 0 {
+		x.Uin32Map = nil
+	}
+	if len(x.Uin64Map) > 0 {
+		x.Uin64Map = nil
+	}
+	if len(x.Flt32Map) > 0 {
+		x.Flt32Map = nil
+	}
+	if len(x.Flt64Map) > 0 {
+		x.Flt64Map = nil
+	}
+	if len(x.BytsMap) > 0 {
+		x.BytsMap = nil
+	}
 	if x.TestOneof != nil {
 		x.TestOneof = nil
 	}
-	MsgPool.Put(x)
+	fastjsonpbMsgUnknown.Reset(x.unknownFields)
 }
 
-func (x *Msg) IsEmptyBol() bool {
+func (x *Msg) Destructor() {
+	if x == nil {
+		panic("type Msg is nil")
+	}
+	x.Bol = false
+	x.Str = ""
+	x.In32 = 0
+	x.In64 = 0
+	x.Uin32 = 0
+	x.Uin64 = 0
+	x.Flt32 = 0
+	x.Flt64 = 0
+	x.Byts = nil
+	x.BolArr = x.BolArr[:0]
+	for i, _ := range x.StrArr {
+		x.StrArr[i] = ""
+	}
+	x.StrArr = x.StrArr[:0]
+	x.In32Arr = x.In32Arr[:0]
+	x.In64Arr = x.In64Arr[:0]
+	x.Uin32Arr = x.Uin32Arr[:0]
+	x.Uin64Arr = x.Uin64Arr[:0]
+	x.Flt32Arr = x.Flt32Arr[:0]
+	x.Flt64Arr = x.Flt64Arr[:0]
+	for i, _ := range x.BytsArr {
+		x.BytsArr[i] = nil
+	}
+	x.BytsArr = x.BytsArr[:0]
+	for k, _ := range x.BolMap {
+		delete(x.BolMap, k)
+	}
+	for k, _ := range x.StringMap {
+		delete(x.StringMap, k)
+	}
+	for k, _ := range x.In32Map {
+		delete(x.In32Map, k)
+	}
+	for k, _ := range x.In64Map {
+		delete(x.In64Map, k)
+	}
+	for k, _ := range x.Uin32Map {
+		delete(x.Uin32Map, k)
+	}
+	for k, _ := range x.Uin64Map {
+		delete(x.Uin64Map, k)
+	}
+	for k, _ := range x.Flt32Map {
+		delete(x.Flt32Map, k)
+	}
+	for k, _ := range x.Flt64Map {
+		delete(x.Flt64Map, k)
+	}
+	for k, _ := range x.BytsMap {
+		delete(x.BytsMap, k)
+	}
+	if x.TestOneof != nil {
+		x.TestOneof = nil
+	}
+	fastjsonpbMsgUnknown.Reset(x.unknownFields)
+	MsgPool.Put(x)
+}
+
+func (x *Msg) IsEmptyBol() bool {
 	return x.GetBol() == false
 }
 
@@ -686,153 +1431,170 @@ func (x *Msg) IsEmptyFlt64() bool {
 }
 
 func (x *Msg) IsEmptyByts() bool {
-	return x.GetByts() == nil
+	return len(x.GetByts()) == 0
 }
 
 func (x *Msg) IsEmptyBolArr() bool {
-	return x.GetBolArr() == nil
+	return len(x.GetBolArr()) == 0
 }
 
 func (x *Msg) IsEmptyStrArr() bool {
-	return x.GetStrArr() == nil
+	return len(x.GetStrArr()) == 0
 }
 
 func (x *Msg) IsEmptyIn32Arr() bool {
-	return x.GetIn32Arr() == nil
+	return len(x.GetIn32Arr()) == 0
 }
 
 func (x *Msg) IsEmptyIn64Arr() bool {
-	return x.GetIn64Arr() == nil
+	return len(x.GetIn64Arr()) == 0
 }
 
 func (x *Msg) IsEmptyUin32Arr() bool {
-	return x.GetUin32Arr() == nil
+	return len(x.GetUin32Arr()) == 0
 }
 
 func (x *Msg) IsEmptyUin64Arr() bool {
-	return x.GetUin64Arr() == nil
+	return len(x.GetUin64Arr()) == 0
 }
 
 func (x *Msg) IsEmptyFlt32Arr() bool {
-	return x.GetFlt32Arr() == nil
+	return len(x.GetFlt32Arr()) == 0
 }
 
 func (x *Msg) IsEmptyFlt64Arr() bool {
-	return x.GetFlt64Arr() == nil
+	return len(x.GetFlt64Arr()) == 0
 }
 
 func (x *Msg) IsEmptyBytsArr() bool {
-	return x.GetBytsArr() == nil
+	return len(x.GetBytsArr()) == 0
 }
 
 func (x *Msg) IsEmptyBolMap() bool {
-	return x.GetBolMap() == nil
+	return len(x.GetBolMap()) == 0
 }
 
 func (x *Msg) IsEmptyStringMap() bool {
-	return x.GetStringMap() == nil
+	return len(x.GetStringMap()) == 0
 }
 
 func (x *Msg) IsEmptyIn32Map() bool {
-	return x.GetIn32Map() == nil
+	return len(x.GetIn32Map()) == 0
 }
 
 func (x *Msg) IsEmptyIn64Map() bool {
-	return x.GetIn64Map() == nil
+	return len(x.GetIn64Map()) == 0
 }
 
 func (x *Msg) IsEmptyUin32Map() bool {
-	return x.GetUin32Map() == nil
+	return len(x.GetUin32Map()) == 0
 }
 
 func (x *Msg) IsEmptyUin64Map() bool {
-	return x.GetUin64Map() == nil
+	return len(x.GetUin64Map()) == 0
 }
 
 func (x *Msg) IsEmptyFlt32Map() bool {
-	return x.GetFlt32Map() == nil
+	return len(x.GetFlt32Map()) == 0
 }
 
 func (x *Msg) IsEmptyFlt64Map() bool {
-	return x.GetFlt64Map() == nil
+	return len(x.GetFlt64Map()) == 0
 }
 
 func (x *Msg) IsEmptyBytsMap() bool {
-	return x.GetBytsMap() == nil
+	return len(x.GetBytsMap()) == 0
 }
 
 func (x *Example) FastMarshal(buf *buffer.Buffer) {
 	if x == nil {
 		buf.WriteString("{}")
+		return
 	}
 	buf.WriteString("{")
-	if !x.IsEmptyBol() {
+	if !x.IsEmptyBol() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("bol")
 		buf.WriteString(":")
 		buf.WriteBool(x.GetBol())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyStr() {
+	if !x.IsEmptyStr() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("str")
 		buf.WriteString(":")
 		buf.WriteStringWithQuote(x.GetStr())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn32() {
+	if !x.IsEmptyIn32() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("in32")
 		buf.WriteString(":")
 		buf.WriteInt32(x.GetIn32())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn64() {
+	if !x.IsEmptyIn64() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("in64")
 		buf.WriteString(":")
-		buf.WriteInt64(x.GetIn64())
+		if buf.QuoteInt64() {
+			buf.WriteByte('"')
+			buf.WriteInt64(x.GetIn64())
+			buf.WriteByte('"')
+		} else {
+			buf.WriteInt64(x.GetIn64())
+		}
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin32() {
+	if !x.IsEmptyUin32() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("uin32")
 		buf.WriteString(":")
 		buf.WriteUint32(x.GetUin32())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin64() {
+	if !x.IsEmptyUin64() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("uin64")
 		buf.WriteString(":")
-		buf.WriteUint64(x.GetUin64())
+		if buf.QuoteInt64() {
+			buf.WriteByte('"')
+			buf.WriteUint64(x.GetUin64())
+			buf.WriteByte('"')
+		} else {
+			buf.WriteUint64(x.GetUin64())
+		}
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt32() {
+	if !x.IsEmptyFlt32() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("flt32")
 		buf.WriteString(":")
 		buf.WriteFloat32(x.GetFlt32())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt64() {
+	if !x.IsEmptyFlt64() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("flt64")
 		buf.WriteString(":")
 		buf.WriteFloat64(x.GetFlt64())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyByts() {
+	if !x.IsEmptyByts() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("byts")
 		buf.WriteString(":")
 		buf.WriteBytes(x.GetByts())
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyTyp() {
+	if !x.IsEmptyTyp() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("typ")
 		buf.WriteString(":")
-		buf.WriteStringWithQuote(x.GetTyp().String())
+		if s, ok := Typ_name[int32(x.GetTyp())]; ok && !buf.UseEnumNumbers() {
+			buf.WriteStringWithQuote(s)
+		} else {
+			buf.WriteInt32(int32(x.GetTyp()))
+		}
 		buf.WriteString(",")
 	}
 
@@ -841,11 +1603,21 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(":")
 		x.GetMsg().FastMarshal(buf)
 		buf.WriteString(",")
+	} else if buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("msg")
+		buf.WriteString(":")
+		buf.WriteString("null")
+		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyBolArr() {
-		buf.WriteStringWithQuote("bolArr")
-		buf.WriteString(":")
+	if !x.IsEmptyBolArr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("bol_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("bolArr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.BolArr {
 			buf.WriteBool(x.BolArr[i])
@@ -856,9 +1628,14 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyStrArr() {
-		buf.WriteStringWithQuote("strArr")
-		buf.WriteString(":")
+	if !x.IsEmptyStrArr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("str_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("strArr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.StrArr {
 			buf.WriteStringWithQuote(x.StrArr[i])
@@ -869,9 +1646,14 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn32Arr() {
-		buf.WriteStringWithQuote("in32Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyIn32Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in32_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("in32Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.In32Arr {
 			buf.WriteInt32(x.In32Arr[i])
@@ -882,12 +1664,23 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn64Arr() {
-		buf.WriteStringWithQuote("in64Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyIn64Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in64_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("in64Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.In64Arr {
-			buf.WriteInt64(x.In64Arr[i])
+			if buf.QuoteInt64() {
+				buf.WriteByte('"')
+				buf.WriteInt64(x.In64Arr[i])
+				buf.WriteByte('"')
+			} else {
+				buf.WriteInt64(x.In64Arr[i])
+			}
 			buf.WriteString(",")
 		}
 		buf.FixSymbol()
@@ -895,9 +1688,14 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin32Arr() {
-		buf.WriteStringWithQuote("uin32Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyUin32Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin32_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("uin32Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.Uin32Arr {
 			buf.WriteUint32(x.Uin32Arr[i])
@@ -908,12 +1706,23 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin64Arr() {
-		buf.WriteStringWithQuote("uin64Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyUin64Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin64_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("uin64Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.Uin64Arr {
-			buf.WriteUint64(x.Uin64Arr[i])
+			if buf.QuoteInt64() {
+				buf.WriteByte('"')
+				buf.WriteUint64(x.Uin64Arr[i])
+				buf.WriteByte('"')
+			} else {
+				buf.WriteUint64(x.Uin64Arr[i])
+			}
 			buf.WriteString(",")
 		}
 		buf.FixSymbol()
@@ -921,9 +1730,14 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt32Arr() {
-		buf.WriteStringWithQuote("flt32Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyFlt32Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("flt32_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("flt32Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.Flt32Arr {
 			buf.WriteFloat32(x.Flt32Arr[i])
@@ -934,9 +1748,14 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt64Arr() {
-		buf.WriteStringWithQuote("flt64Arr")
-		buf.WriteString(":")
+	if !x.IsEmptyFlt64Arr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("flt64_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("flt64Arr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.Flt64Arr {
 			buf.WriteFloat64(x.Flt64Arr[i])
@@ -947,9 +1766,14 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyBytsArr() {
-		buf.WriteStringWithQuote("bytsArr")
-		buf.WriteString(":")
+	if !x.IsEmptyBytsArr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("byts_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("bytsArr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.BytsArr {
 			buf.WriteBytes(x.BytsArr[i])
@@ -960,12 +1784,21 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyTypArr() {
-		buf.WriteStringWithQuote("typArr")
-		buf.WriteString(":")
+	if !x.IsEmptyTypArr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("typ_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("typArr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.TypArr {
-			buf.WriteStringWithQuote(x.TypArr[i].String())
+			if s, ok := Typ_name[int32(x.TypArr[i])]; ok && !buf.UseEnumNumbers() {
+				buf.WriteStringWithQuote(s)
+			} else {
+				buf.WriteInt32(int32(x.TypArr[i]))
+			}
 			buf.WriteString(",")
 		}
 		buf.FixSymbol()
@@ -973,9 +1806,14 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyMsgArr() {
-		buf.WriteStringWithQuote("msgArr")
-		buf.WriteString(":")
+	if !x.IsEmptyMsgArr() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("msg_arr")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("msgArr")
+			buf.WriteString(":")
+		}
 		buf.WriteString("[")
 		for i, _ := range x.MsgArr {
 			x.MsgArr[i].FastMarshal(buf)
@@ -986,209 +1824,520 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyBolMap() {
-		buf.WriteStringWithQuote("bolMap")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.BolMap {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyBolMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("bol_map")
 			buf.WriteString(":")
-			buf.WriteBool(x.BolMap[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("bolMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.BolMap))
+			for k := range x.BolMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteBool(x.BolMap[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.BolMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteBool(x.BolMap[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyStringMap() {
-		buf.WriteStringWithQuote("stringMap")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.StringMap {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyStringMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("string_map")
 			buf.WriteString(":")
-			buf.WriteStringWithQuote(x.StringMap[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("stringMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.StringMap))
+			for k := range x.StringMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.StringMap[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.StringMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.StringMap[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn32Map() {
-		buf.WriteStringWithQuote("in32Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.In32Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyIn32Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in32_map")
 			buf.WriteString(":")
-			buf.WriteInt32(x.In32Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("in32Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.In32Map))
+			for k := range x.In32Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteInt32(x.In32Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.In32Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteInt32(x.In32Map[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyIn64Map() {
-		buf.WriteStringWithQuote("in64Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.In64Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyIn64Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in64_map")
 			buf.WriteString(":")
-			buf.WriteInt64(x.In64Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("in64Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.In64Map))
+			for k := range x.In64Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if buf.QuoteInt64() {
+					buf.WriteByte('"')
+					buf.WriteInt64(x.In64Map[k])
+					buf.WriteByte('"')
+				} else {
+					buf.WriteInt64(x.In64Map[k])
+				}
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.In64Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if buf.QuoteInt64() {
+					buf.WriteByte('"')
+					buf.WriteInt64(x.In64Map[k])
+					buf.WriteByte('"')
+				} else {
+					buf.WriteInt64(x.In64Map[k])
+				}
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin32Map() {
-		buf.WriteStringWithQuote("uin32Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.Uin32Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyUin32Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin32_map")
 			buf.WriteString(":")
-			buf.WriteUint32(x.Uin32Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("uin32Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.Uin32Map))
+			for k := range x.Uin32Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteUint32(x.Uin32Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Uin32Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteUint32(x.Uin32Map[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyUin64Map() {
-		buf.WriteStringWithQuote("uin64Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.Uin64Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyUin64Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin64_map")
 			buf.WriteString(":")
-			buf.WriteUint64(x.Uin64Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("uin64Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.Uin64Map))
+			for k := range x.Uin64Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if buf.QuoteInt64() {
+					buf.WriteByte('"')
+					buf.WriteUint64(x.Uin64Map[k])
+					buf.WriteByte('"')
+				} else {
+					buf.WriteUint64(x.Uin64Map[k])
+				}
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Uin64Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if buf.QuoteInt64() {
+					buf.WriteByte('"')
+					buf.WriteUint64(x.Uin64Map[k])
+					buf.WriteByte('"')
+				} else {
+					buf.WriteUint64(x.Uin64Map[k])
+				}
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt32Map() {
-		buf.WriteStringWithQuote("flt32Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.Flt32Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyFlt32Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("flt32_map")
 			buf.WriteString(":")
-			buf.WriteFloat32(x.Flt32Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("flt32Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.Flt32Map))
+			for k := range x.Flt32Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteFloat32(x.Flt32Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Flt32Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteFloat32(x.Flt32Map[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyFlt64Map() {
-		buf.WriteStringWithQuote("flt64Map")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.Flt64Map {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyFlt64Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("flt64_map")
 			buf.WriteString(":")
-			buf.WriteFloat64(x.Flt64Map[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("flt64Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.Flt64Map))
+			for k := range x.Flt64Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteFloat64(x.Flt64Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Flt64Map {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteFloat64(x.Flt64Map[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyBytsMap() {
-		buf.WriteStringWithQuote("bytsMap")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.BytsMap {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyBytsMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("byts_map")
 			buf.WriteString(":")
-			buf.WriteBytes(x.BytsMap[k])
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("bytsMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.BytsMap))
+			for k := range x.BytsMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteBytes(x.BytsMap[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.BytsMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteBytes(x.BytsMap[k])
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyTypMap() {
-		buf.WriteStringWithQuote("typMap")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.TypMap {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyTypMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("typ_map")
 			buf.WriteString(":")
-			buf.WriteStringWithQuote(x.TypMap[k].String())
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("typMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.TypMap))
+			for k := range x.TypMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if s, ok := Typ_name[int32(x.TypMap[k])]; ok && !buf.UseEnumNumbers() {
+					buf.WriteStringWithQuote(s)
+				} else {
+					buf.WriteInt32(int32(x.TypMap[k]))
+				}
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.TypMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if s, ok := Typ_name[int32(x.TypMap[k])]; ok && !buf.UseEnumNumbers() {
+					buf.WriteStringWithQuote(s)
+				} else {
+					buf.WriteInt32(int32(x.TypMap[k]))
+				}
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyMsgMap() {
-		buf.WriteStringWithQuote("msgMap")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.MsgMap {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyMsgMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("msg_map")
 			buf.WriteString(":")
-			x.MsgMap[k].FastMarshal(buf)
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("msgMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.MsgMap))
+			for k := range x.MsgMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				x.MsgMap[k].FastMarshal(buf)
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.MsgMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				x.MsgMap[k].FastMarshal(buf)
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyNestedTyp() {
-		buf.WriteStringWithQuote("nestedTyp")
-		buf.WriteString(":")
-		buf.WriteStringWithQuote(x.GetNestedTyp().String())
+	if !x.IsEmptyNestedTyp() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("nested_typ")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("nestedTyp")
+			buf.WriteString(":")
+		}
+		if s, ok := Example_NestedTyp_name[int32(x.GetNestedTyp())]; ok && !buf.UseEnumNumbers() {
+			buf.WriteStringWithQuote(s)
+		} else {
+			buf.WriteInt32(int32(x.GetNestedTyp()))
+		}
 		buf.WriteString(",")
 	}
 
 	if !x.IsEmptyNestedMsg() {
-		buf.WriteStringWithQuote("nestedMsg")
-		buf.WriteString(":")
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("nested_msg")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("nestedMsg")
+			buf.WriteString(":")
+		}
 		x.GetNestedMsg().FastMarshal(buf)
 		buf.WriteString(",")
+	} else if buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("nested_msg")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("nestedMsg")
+			buf.WriteString(":")
+		}
+		buf.WriteString("null")
+		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyNestedTypMap() {
-		buf.WriteStringWithQuote("nestedTypMap")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.NestedTypMap {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyNestedTypMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("nested_typ_map")
 			buf.WriteString(":")
-			buf.WriteStringWithQuote(x.NestedTypMap[k].String())
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("nestedTypMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.NestedTypMap))
+			for k := range x.NestedTypMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if s, ok := Example_NestedTyp_name[int32(x.NestedTypMap[k])]; ok && !buf.UseEnumNumbers() {
+					buf.WriteStringWithQuote(s)
+				} else {
+					buf.WriteInt32(int32(x.NestedTypMap[k]))
+				}
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.NestedTypMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				if s, ok := Example_NestedTyp_name[int32(x.NestedTypMap[k])]; ok && !buf.UseEnumNumbers() {
+					buf.WriteStringWithQuote(s)
+				} else {
+					buf.WriteInt32(int32(x.NestedTypMap[k]))
+				}
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
 		buf.WriteString(",")
 	}
 
-	if !x.IsEmptyNestedMsgMap() {
-		buf.WriteStringWithQuote("nestedMsgMap")
-		buf.WriteString(":")
-		buf.WriteString("{")
-		for k, _ := range x.NestedMsgMap {
-			buf.WriteStringWithQuote(k)
+	if !x.IsEmptyNestedMsgMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("nested_msg_map")
 			buf.WriteString(":")
-			x.NestedMsgMap[k].FastMarshal(buf)
-			buf.WriteString(",")
+		} else {
+			buf.WriteStringWithQuote("nestedMsgMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.NestedMsgMap))
+			for k := range x.NestedMsgMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				x.NestedMsgMap[k].FastMarshal(buf)
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.NestedMsgMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				x.NestedMsgMap[k].FastMarshal(buf)
+				buf.WriteString(",")
+			}
 		}
 		buf.FixSymbol()
 		buf.WriteString("}")
@@ -1197,68 +2346,131 @@ func (x *Example) FastMarshal(buf *buffer.Buffer) {
 
 	if x.TestOneof != nil {
 		if _, ok := x.GetTestOneof().(*Example_OneofBol); ok {
-			buf.WriteStringWithQuote("oneofBol")
-			buf.WriteString(":")
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_bol")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofBol")
+				buf.WriteString(":")
+			}
 			buf.WriteBool(x.GetOneofBol())
 			buf.WriteString(",")
 
 		} else if _, ok := x.GetTestOneof().(*Example_OneofStr); ok {
-			buf.WriteStringWithQuote("oneofStr")
-			buf.WriteString(":")
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_str")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofStr")
+				buf.WriteString(":")
+			}
 			buf.WriteStringWithQuote(x.GetOneofStr())
 			buf.WriteString(",")
 
 		} else if _, ok := x.GetTestOneof().(*Example_OneofIn32); ok {
-			buf.WriteStringWithQuote("oneofIn32")
-			buf.WriteString(":")
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_in32")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofIn32")
+				buf.WriteString(":")
+			}
 			buf.WriteInt32(x.GetOneofIn32())
 			buf.WriteString(",")
 
 		} else if _, ok := x.GetTestOneof().(*Example_OneofIn64); ok {
-			buf.WriteStringWithQuote("oneofIn64")
-			buf.WriteString(":")
-			buf.WriteInt64(x.GetOneofIn64())
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_in64")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofIn64")
+				buf.WriteString(":")
+			}
+			if buf.QuoteInt64() {
+				buf.WriteByte('"')
+				buf.WriteInt64(x.GetOneofIn64())
+				buf.WriteByte('"')
+			} else {
+				buf.WriteInt64(x.GetOneofIn64())
+			}
 			buf.WriteString(",")
 
 		} else if _, ok := x.GetTestOneof().(*Example_OneofUin32); ok {
-			buf.WriteStringWithQuote("oneofUin32")
-			buf.WriteString(":")
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_uin32")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofUin32")
+				buf.WriteString(":")
+			}
 			buf.WriteUint32(x.GetOneofUin32())
 			buf.WriteString(",")
 
 		} else if _, ok := x.GetTestOneof().(*Example_OneofUin64); ok {
-			buf.WriteStringWithQuote("oneofUin64")
-			buf.WriteString(":")
-			buf.WriteUint64(x.GetOneofUin64())
-			buf.WriteString(",")
-
-		} else if _, ok := x.GetTestOneof().(*Example_OneofFlt32); ok {
-			buf.WriteStringWithQuote("oneofFlt32")
-			buf.WriteString(":")
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_uin64")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofUin64")
+				buf.WriteString(":")
+			}
+			if buf.QuoteInt64() {
+				buf.WriteByte('"')
+				buf.WriteUint64(x.GetOneofUin64())
+				buf.WriteByte('"')
+			} else {
+				buf.WriteUint64(x.GetOneofUin64())
+			}
+			buf.WriteString(",")
+
+		} else if _, ok := x.GetTestOneof().(*Example_OneofFlt32); ok {
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_flt32")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofFlt32")
+				buf.WriteString(":")
+			}
 			buf.WriteFloat32(x.GetOneofFlt32())
 			buf.WriteString(",")
 
 		} else if _, ok := x.GetTestOneof().(*Example_OneofFlt64); ok {
-			buf.WriteStringWithQuote("oneofFlt64")
-			buf.WriteString(":")
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_flt64")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofFlt64")
+				buf.WriteString(":")
+			}
 			buf.WriteFloat64(x.GetOneofFlt64())
 			buf.WriteString(",")
 
 		} else if _, ok := x.GetTestOneof().(*Example_OneofByts); ok {
-			buf.WriteStringWithQuote("oneofByts")
-			buf.WriteString(":")
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_byts")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofByts")
+				buf.WriteString(":")
+			}
 			buf.WriteBytes(x.GetOneofByts())
 			buf.WriteString(",")
 
 		} else if _, ok := x.GetTestOneof().(*Example_OneofMsg); ok {
-			buf.WriteStringWithQuote("oneofMsg")
-			buf.WriteString(":")
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_msg")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofMsg")
+				buf.WriteString(":")
+			}
 			x.GetOneofMsg().FastMarshal(buf)
 			buf.WriteString(",")
 		}
 
 	}
 
+	fastjsonpbExampleUnknown.Marshal(buf, x.unknownFields)
 	buf.FixSymbol()
 	buf.WriteString("}")
 }
@@ -1267,157 +2479,320 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 	if x == nil {
 		panic("type Example is nil")
 	}
+	var seen [1]uint64
 	p.Symbol('{')
 	for !p.IsSymbol('}') {
-		key := p.Str()
+		key := p.Key()
 		p.AssertSymbol(':')
 		switch key {
 		case "bol":
+			if p.SkipDuplicate(seen[:], 0, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Bol = p.Bol()
 
 		case "str":
+			if p.SkipDuplicate(seen[:], 1, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Str = p.Str()
 
 		case "in32":
+			if p.SkipDuplicate(seen[:], 2, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.In32 = p.Int32()
 
 		case "in64":
+			if p.SkipDuplicate(seen[:], 3, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.In64 = p.Int64()
 
 		case "uin32":
+			if p.SkipDuplicate(seen[:], 4, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Uin32 = p.Uint32()
 
 		case "uin64":
+			if p.SkipDuplicate(seen[:], 5, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Uin64 = p.Uint64()
 
 		case "flt32":
+			if p.SkipDuplicate(seen[:], 6, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Flt32 = p.Float32()
 
 		case "flt64":
+			if p.SkipDuplicate(seen[:], 7, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Flt64 = p.Float64()
 
 		case "byts":
+			if p.SkipDuplicate(seen[:], 8, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Byts = p.Bytes()
 
 		case "typ":
+			if p.SkipDuplicate(seen[:], 9, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			t, s, i := p.Enum()
 			if t == jsonparser.EnumNumber {
-				x.Typ.Set(i)
+				x.Typ = Typ(i)
 			} else {
 				x.Typ.SetByStr(s)
 			}
 
 		case "msg":
-			x.Msg = MsgNew()
+			if p.SkipDuplicate(seen[:], 10, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			if x.Msg != nil {
+				if !p.Merge() {
+					x.Msg.FastReset()
+				}
+			} else {
+				x.Msg = MsgArenaNew(p.Arena())
+			}
 			x.Msg.FastUnmarshal(p)
 
-		case "bolArr":
+		case "bolArr", "bol_arr":
+			if p.SkipDuplicate(seen[:], 11, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]bool, 0)
+			arr := x.BolArr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Bol())
+				arr = p.Arena().AppendBool(arr, p.Bol())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.BolArr = arr
 
-		case "strArr":
+		case "strArr", "str_arr":
+			if p.SkipDuplicate(seen[:], 12, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]string, 0)
+			arr := x.StrArr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Str())
+				arr = p.Arena().AppendString(arr, p.Str())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.StrArr = arr
 
-		case "in32Arr":
+		case "in32Arr", "in32_arr":
+			if p.SkipDuplicate(seen[:], 13, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]int32, 0)
+			arr := x.In32Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Int32())
+				arr = p.Arena().AppendInt32(arr, p.Int32())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.In32Arr = arr
 
-		case "in64Arr":
+		case "in64Arr", "in64_arr":
+			if p.SkipDuplicate(seen[:], 14, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]int64, 0)
+			arr := x.In64Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Int64())
+				arr = p.Arena().AppendInt64(arr, p.Int64())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.In64Arr = arr
 
-		case "uin32Arr":
+		case "uin32Arr", "uin32_arr":
+			if p.SkipDuplicate(seen[:], 15, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]uint32, 0)
+			arr := x.Uin32Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Uint32())
+				arr = p.Arena().AppendUint32(arr, p.Uint32())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.Uin32Arr = arr
 
-		case "uin64Arr":
+		case "uin64Arr", "uin64_arr":
+			if p.SkipDuplicate(seen[:], 16, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]uint64, 0)
+			arr := x.Uin64Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Uint64())
+				arr = p.Arena().AppendUint64(arr, p.Uint64())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.Uin64Arr = arr
 
-		case "flt32Arr":
+		case "flt32Arr", "flt32_arr":
+			if p.SkipDuplicate(seen[:], 17, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]float32, 0)
+			arr := x.Flt32Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Float32())
+				arr = p.Arena().AppendFloat32(arr, p.Float32())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.Flt32Arr = arr
 
-		case "flt64Arr":
+		case "flt64Arr", "flt64_arr":
+			if p.SkipDuplicate(seen[:], 18, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]float64, 0)
+			arr := x.Flt64Arr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Float64())
+				arr = p.Arena().AppendFloat64(arr, p.Float64())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.Flt64Arr = arr
 
-		case "bytsArr":
+		case "bytsArr", "byts_arr":
+			if p.SkipDuplicate(seen[:], 19, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([][]byte, 0)
+			arr := x.BytsArr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				arr = append(arr, p.Bytes())
+				arr = p.Arena().AppendBytes(arr, p.Bytes())
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.BytsArr = arr
 
-		case "typArr":
+		case "typArr", "typ_arr":
+			if p.SkipDuplicate(seen[:], 20, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]Typ, 0)
+			arr := x.TypArr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
 				var e Typ
 				t, s, i := p.Enum()
 				if t == jsonparser.EnumNumber {
-					e = e.Get(i)
+					e = Typ(i)
 				} else {
 					e = e.GetByStr(s)
 				}
@@ -1428,25 +2803,54 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol(']')
 			x.TypArr = arr
 
-		case "msgArr":
+		case "msgArr", "msg_arr":
+			if p.SkipDuplicate(seen[:], 21, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('[')
-			arr := make([]*Msg, 0)
+			arr := x.MsgArr
+			if !p.Merge() && len(arr) > 0 {
+				arr = nil
+			}
 			for !p.IsSymbol(']') {
-				tmp := MsgNew()
+				tmp := MsgArenaNew(p.Arena())
 				tmp.FastUnmarshal(p)
-				arr = append(arr, tmp)
+				arr = MsgArenaAppend(p.Arena(), arr, tmp)
 				p.AssertSymbol(',')
 			}
 			p.AssertSymbol(',')
 			p.Symbol(']')
 			x.MsgArr = arr
 
-		case "bolMap":
+		case "bolMap", "bol_map":
+			if p.SkipDuplicate(seen[:], 22, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]bool)
+			m := x.BolMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]bool)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Bol()
 				p.AssertSymbol(',')
 			}
@@ -1454,12 +2858,32 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.BolMap = m
 
-		case "stringMap":
+		case "stringMap", "string_map":
+			if p.SkipDuplicate(seen[:], 23, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]string)
+			m := x.StringMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]string)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Str()
 				p.AssertSymbol(',')
 			}
@@ -1467,12 +2891,32 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.StringMap = m
 
-		case "in32Map":
+		case "in32Map", "in32_map":
+			if p.SkipDuplicate(seen[:], 24, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]int32)
+			m := x.In32Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]int32)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Int32()
 				p.AssertSymbol(',')
 			}
@@ -1480,12 +2924,32 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.In32Map = m
 
-		case "in64Map":
+		case "in64Map", "in64_map":
+			if p.SkipDuplicate(seen[:], 25, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]int64)
+			m := x.In64Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]int64)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Int64()
 				p.AssertSymbol(',')
 			}
@@ -1493,12 +2957,32 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.In64Map = m
 
-		case "uin32Map":
+		case "uin32Map", "uin32_map":
+			if p.SkipDuplicate(seen[:], 26, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]uint32)
+			m := x.Uin32Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]uint32)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Uint32()
 				p.AssertSymbol(',')
 			}
@@ -1506,12 +2990,32 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.Uin32Map = m
 
-		case "uin64Map":
+		case "uin64Map", "uin64_map":
+			if p.SkipDuplicate(seen[:], 27, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]uint64)
+			m := x.Uin64Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]uint64)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Uint64()
 				p.AssertSymbol(',')
 			}
@@ -1519,12 +3023,32 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.Uin64Map = m
 
-		case "flt32Map":
+		case "flt32Map", "flt32_map":
+			if p.SkipDuplicate(seen[:], 28, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]float32)
+			m := x.Flt32Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]float32)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Float32()
 				p.AssertSymbol(',')
 			}
@@ -1532,12 +3056,32 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.Flt32Map = m
 
-		case "flt64Map":
+		case "flt64Map", "flt64_map":
+			if p.SkipDuplicate(seen[:], 29, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]float64)
+			m := x.Flt64Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]float64)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Float64()
 				p.AssertSymbol(',')
 			}
@@ -1545,12 +3089,32 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.Flt64Map = m
 
-		case "bytsMap":
+		case "bytsMap", "byts_map":
+			if p.SkipDuplicate(seen[:], 30, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string][]byte)
+			m := x.BytsMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string][]byte)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				m[key] = p.Bytes()
 				p.AssertSymbol(',')
 			}
@@ -1558,16 +3122,36 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.BytsMap = m
 
-		case "typMap":
+		case "typMap", "typ_map":
+			if p.SkipDuplicate(seen[:], 31, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]Typ)
+			m := x.TypMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]Typ)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				var e Typ
 				t, s, i := p.Enum()
 				if t == jsonparser.EnumNumber {
-					e = e.Get(i)
+					e = Typ(i)
 				} else {
 					e = e.GetByStr(s)
 				}
@@ -1578,13 +3162,33 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.TypMap = m
 
-		case "msgMap":
+		case "msgMap", "msg_map":
+			if p.SkipDuplicate(seen[:], 32, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]*Msg)
+			m := x.MsgMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]*Msg)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
-				tmp := MsgNew()
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
+				tmp := MsgArenaNew(p.Arena())
 				tmp.FastUnmarshal(p)
 				m[key] = tmp
 				p.AssertSymbol(',')
@@ -1593,28 +3197,66 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.MsgMap = m
 
-		case "nestedTyp":
+		case "nestedTyp", "nested_typ":
+			if p.SkipDuplicate(seen[:], 33, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			t, s, i := p.Enum()
 			if t == jsonparser.EnumNumber {
-				x.NestedTyp.Set(i)
+				x.NestedTyp = Example_NestedTyp(i)
 			} else {
 				x.NestedTyp.SetByStr(s)
 			}
 
-		case "nestedMsg":
-			x.NestedMsg = Example_NestedMsgNew()
+		case "nestedMsg", "nested_msg":
+			if p.SkipDuplicate(seen[:], 34, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			if x.NestedMsg != nil {
+				if !p.Merge() {
+					x.NestedMsg.FastReset()
+				}
+			} else {
+				x.NestedMsg = Example_NestedMsgArenaNew(p.Arena())
+			}
 			x.NestedMsg.FastUnmarshal(p)
 
-		case "nestedTypMap":
+		case "nestedTypMap", "nested_typ_map":
+			if p.SkipDuplicate(seen[:], 35, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]Example_NestedTyp)
+			m := x.NestedTypMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]Example_NestedTyp)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
 				var e Example_NestedTyp
 				t, s, i := p.Enum()
 				if t == jsonparser.EnumNumber {
-					e = e.Get(i)
+					e = Example_NestedTyp(i)
 				} else {
 					e = e.GetByStr(s)
 				}
@@ -1625,13 +3267,33 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.NestedTypMap = m
 
-		case "nestedMsgMap":
+		case "nestedMsgMap", "nested_msg_map":
+			if p.SkipDuplicate(seen[:], 36, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			p.Symbol('{')
-			m := make(map[string]*Example_NestedMsg)
+			m := x.NestedMsgMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]*Example_NestedMsg)
+			}
+			var keys map[string]struct{}
 			for !p.IsSymbol('}') {
 				key := p.Str()
 				p.AssertSymbol(':')
-				tmp := Example_NestedMsgNew()
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
+				tmp := Example_NestedMsgArenaNew(p.Arena())
 				tmp.FastUnmarshal(p)
 				m[key] = tmp
 				p.AssertSymbol(',')
@@ -1640,49 +3302,145 @@ func (x *Example) FastUnmarshal(p *jsonparser.Parser) {
 			p.Symbol('}')
 			x.NestedMsgMap = m
 
-		case "oneofBol":
-			tmp := &Example_OneofBol{}
+		case "oneofBol", "oneof_bol":
+			if p.SkipDuplicate(seen[:], 37, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Example_OneofBol)
+			if !ok {
+				tmp = &Example_OneofBol{}
+			}
 			tmp.OneofBol = p.Bol()
 			x.TestOneof = tmp
-		case "oneofStr":
-			tmp := &Example_OneofStr{}
+		case "oneofStr", "oneof_str":
+			if p.SkipDuplicate(seen[:], 37, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Example_OneofStr)
+			if !ok {
+				tmp = &Example_OneofStr{}
+			}
 			tmp.OneofStr = p.Str()
 			x.TestOneof = tmp
-		case "oneofIn32":
-			tmp := &Example_OneofIn32{}
+		case "oneofIn32", "oneof_in32":
+			if p.SkipDuplicate(seen[:], 37, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Example_OneofIn32)
+			if !ok {
+				tmp = &Example_OneofIn32{}
+			}
 			tmp.OneofIn32 = p.Int32()
 			x.TestOneof = tmp
-		case "oneofIn64":
-			tmp := &Example_OneofIn64{}
+		case "oneofIn64", "oneof_in64":
+			if p.SkipDuplicate(seen[:], 37, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Example_OneofIn64)
+			if !ok {
+				tmp = &Example_OneofIn64{}
+			}
 			tmp.OneofIn64 = p.Int64()
 			x.TestOneof = tmp
-		case "oneofUin32":
-			tmp := &Example_OneofUin32{}
+		case "oneofUin32", "oneof_uin32":
+			if p.SkipDuplicate(seen[:], 37, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Example_OneofUin32)
+			if !ok {
+				tmp = &Example_OneofUin32{}
+			}
 			tmp.OneofUin32 = p.Uint32()
 			x.TestOneof = tmp
-		case "oneofUin64":
-			tmp := &Example_OneofUin64{}
+		case "oneofUin64", "oneof_uin64":
+			if p.SkipDuplicate(seen[:], 37, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Example_OneofUin64)
+			if !ok {
+				tmp = &Example_OneofUin64{}
+			}
 			tmp.OneofUin64 = p.Uint64()
 			x.TestOneof = tmp
-		case "oneofFlt32":
-			tmp := &Example_OneofFlt32{}
+		case "oneofFlt32", "oneof_flt32":
+			if p.SkipDuplicate(seen[:], 37, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Example_OneofFlt32)
+			if !ok {
+				tmp = &Example_OneofFlt32{}
+			}
 			tmp.OneofFlt32 = p.Float32()
 			x.TestOneof = tmp
-		case "oneofFlt64":
-			tmp := &Example_OneofFlt64{}
+		case "oneofFlt64", "oneof_flt64":
+			if p.SkipDuplicate(seen[:], 37, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Example_OneofFlt64)
+			if !ok {
+				tmp = &Example_OneofFlt64{}
+			}
 			tmp.OneofFlt64 = p.Float64()
 			x.TestOneof = tmp
-		case "oneofByts":
-			tmp := &Example_OneofByts{}
+		case "oneofByts", "oneof_byts":
+			if p.SkipDuplicate(seen[:], 37, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Example_OneofByts)
+			if !ok {
+				tmp = &Example_OneofByts{}
+			}
 			tmp.OneofByts = p.Bytes()
 			x.TestOneof = tmp
-		case "oneofMsg":
-			tmp := &Example_OneofMsg{}
-			tmp.OneofMsg = MsgNew()
+		case "oneofMsg", "oneof_msg":
+			if p.SkipDuplicate(seen[:], 37, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.TestOneof.(*Example_OneofMsg)
+			if !ok {
+				tmp = &Example_OneofMsg{}
+			}
+			if tmp.OneofMsg != nil {
+				if !p.Merge() {
+					tmp.OneofMsg.FastReset()
+				}
+			} else {
+				tmp.OneofMsg = MsgArenaNew(p.Arena())
+			}
 			tmp.OneofMsg.FastUnmarshal(p)
 			x.TestOneof = tmp
 		default:
-			p.PassParse()
+			fastjsonpbExampleUnknown.Append(&x.unknownFields, key, p.UnknownValue(key))
 		}
 		p.AssertSymbol(',')
 	}
@@ -1698,7 +3456,54 @@ func ExampleNew() *Example {
 	}
 	return &Example{}
 }
-func (x *Example) Destructor() {
+
+var fastjsonpbExampleUnknown jsonparser.UnknownTable
+
+type fastjsonpbExampleSlab struct {
+	msgs []Example
+	ptrs []*Example
+}
+
+var fastjsonpbExampleSlabID = arena.NewSlabID()
+
+func fastjsonpbExampleSlabGet(a *arena.Arena) *fastjsonpbExampleSlab {
+	s, _ := a.Slab(fastjsonpbExampleSlabID).(*fastjsonpbExampleSlab)
+	if s == nil {
+		s = &fastjsonpbExampleSlab{}
+		a.SetSlab(fastjsonpbExampleSlabID, s)
+	}
+	return s
+}
+
+func ExampleArenaNew(a *arena.Arena) *Example {
+	if a == nil {
+		return ExampleNew()
+	}
+	s := fastjsonpbExampleSlabGet(a)
+	if len(s.msgs) == cap(s.msgs) {
+		s.msgs = make([]Example, 0, arena.SlabLen(cap(s.msgs)))
+	}
+	s.msgs = s.msgs[:len(s.msgs)+1]
+	return &s.msgs[len(s.msgs)-1]
+}
+
+func ExampleArenaAppend(a *arena.Arena, arr []*Example, v *Example) []*Example {
+	if a == nil || len(arr) < cap(arr) {
+		return append(arr, v)
+	}
+	s := fastjsonpbExampleSlabGet(a)
+	n := arena.GrowLen(cap(arr))
+	if cap(s.ptrs)-len(s.ptrs) < n {
+		s.ptrs = make([]*Example, 0, arena.ChunkLen(cap(s.ptrs), n))
+	}
+	l := len(s.ptrs)
+	s.ptrs = s.ptrs[:l+n]
+	ns := s.ptrs[l : l+len(arr) : l+n]
+	copy(ns, arr)
+	return append(ns, v)
+}
+
+func (x *Example) FastReset() {
 	if x == nil {
 		panic("type Example is nil")
 	}
@@ -1711,51 +3516,180 @@ func (x *Example) Destructor() {
 	x.Flt32 = 0
 	x.Flt64 = 0
 	x.Byts = nil
-	x.Typ.Set(0)
-	x.Msg.Destructor()
+	x.Typ = 0
 	x.Msg = nil
-	x.BolArr = nil
-	x.StrArr = nil
-	x.In32Arr = nil
-	x.In64Arr = nil
-	x.Uin32Arr = nil
-	x.Uin64Arr = nil
-	x.Flt32Arr = nil
-	x.Flt64Arr = nil
-	x.BytsArr = nil
-	x.TypArr = nil
+	if len(x.BolArr) > 0 {
+		x.BolArr = nil
+	}
+	if len(x.StrArr) > 0 {
+		x.StrArr = nil
+	}
+	if len(x.In32Arr) > 0 {
+		x.In32Arr = nil
+	}
+	if len(x.In64Arr) > 0 {
+		x.In64Arr = nil
+	}
+	if len(x.Uin32Arr) > 0 {
+		x.Uin32Arr = nil
+	}
+	if len(x.Uin64Arr) > 0 {
+		x.Uin64Arr = nil
+	}
+	if len(x.Flt32Arr) > 0 {
+		x.Flt32Arr = nil
+	}
+	if len(x.Flt64Arr) > 0 {
+		x.Flt64Arr = nil
+	}
+	if len(x.BytsArr) > 0 {
+		x.BytsArr = nil
+	}
+	if len(x.TypArr) > 0 {
+		x.TypArr = nil
+	}
+	if len(x.MsgArr) > 0 {
+		x.MsgArr = nil
+	}
+	if len(x.BolMap) > 0 {
+		x.BolMap = nil
+	}
+	if len(x.StringMap) > 0 {
+		x.StringMap = nil
+	}
+	if len(x.In32Map) > 0 {
+		x.In32Map = nil
+	}
+	if len(x.In64Map) > 0 {
+		x.In64Map = nil
+	}
+	if len(x.Uin32Map) > 0 {
+		x.Uin32Map = nil
+	}
+	if len(x.Uin64Map) > 0 {
+		x.Uin64Map = nil
+	}
+	if len(x.Flt32Map) > 0 {
+		x.Flt32Map = nil
+	}
+	if len(x.Flt64Map) > 0 {
+		x.Flt64Map = nil
+	}
+	if len(x.BytsMap) > 0 {
+		x.BytsMap = nil
+	}
+	if len(x.TypMap) > 0 {
+		x.TypMap = nil
+	}
+	if len(x.MsgMap) > 0 {
+		x.MsgMap = nil
+	}
+	x.NestedTyp = 0
+	x.NestedMsg = nil
+	if len(x.NestedTypMap) > 0 {
+		x.NestedTypMap = nil
+	}
+	if len(x.NestedMsgMap) > 0 {
+		x.NestedMsgMap = nil
+	}
+	if x.TestOneof != nil {
+		x.TestOneof = nil
+	}
+	fastjsonpbExampleUnknown.Reset(x.unknownFields)
+}
+
+func (x *Example) Destructor() {
+	if x == nil {
+		panic("type Example is nil")
+	}
+	x.Bol = false
+	x.Str = ""
+	x.In32 = 0
+	x.In64 = 0
+	x.Uin32 = 0
+	x.Uin64 = 0
+	x.Flt32 = 0
+	x.Flt64 = 0
+	x.Byts = nil
+	x.Typ = 0
+	if x.Msg != nil {
+		x.Msg.Destructor()
+		x.Msg = nil
+	}
+	x.BolArr = x.BolArr[:0]
+	for i, _ := range x.StrArr {
+		x.StrArr[i] = ""
+	}
+	x.StrArr = x.StrArr[:0]
+	x.In32Arr = x.In32Arr[:0]
+	x.In64Arr = x.In64Arr[:0]
+	x.Uin32Arr = x.Uin32Arr[:0]
+	x.Uin64Arr = x.Uin64Arr[:0]
+	x.Flt32Arr = x.Flt32Arr[:0]
+	x.Flt64Arr = x.Flt64Arr[:0]
+	for i, _ := range x.BytsArr {
+		x.BytsArr[i] = nil
+	}
+	x.BytsArr = x.BytsArr[:0]
+	x.TypArr = x.TypArr[:0]
 	for i, _ := range x.MsgArr {
 		x.MsgArr[i].Destructor()
+		x.MsgArr[i] = nil
 	}
-	x.MsgArr = nil
-	x.BolMap = nil
-	x.StringMap = nil
-	x.In32Map = nil
-	x.In64Map = nil
-	x.Uin32Map = nil
-	x.Uin64Map = nil
-	x.Flt32Map = nil
-	x.Flt64Map = nil
-	x.BytsMap = nil
-	x.TypMap = nil
-	for i, _ := range x.MsgMap {
-		x.MsgMap[i].Destructor()
-	}
-	x.MsgMap = nil
-	x.NestedTyp.Set(0)
-	x.NestedMsg.Destructor()
-	x.NestedMsg = nil
-	x.NestedTypMap = nil
-	for i, _ := range x.NestedMsgMap {
-		x.NestedMsgMap[i].Destructor()
+	x.MsgArr = x.MsgArr[:0]
+	for k, _ := range x.BolMap {
+		delete(x.BolMap, k)
+	}
+	for k, _ := range x.StringMap {
+		delete(x.StringMap, k)
+	}
+	for k, _ := range x.In32Map {
+		delete(x.In32Map, k)
+	}
+	for k, _ := range x.In64Map {
+		delete(x.In64Map, k)
+	}
+	for k, _ := range x.Uin32Map {
+		delete(x.Uin32Map, k)
+	}
+	for k, _ := range x.Uin64Map {
+		delete(x.Uin64Map, k)
+	}
+	for k, _ := range x.Flt32Map {
+		delete(x.Flt32Map, k)
+	}
+	for k, _ := range x.Flt64Map {
+		delete(x.Flt64Map, k)
+	}
+	for k, _ := range x.BytsMap {
+		delete(x.BytsMap, k)
+	}
+	for k, _ := range x.TypMap {
+		delete(x.TypMap, k)
+	}
+	for k, _ := range x.MsgMap {
+		x.MsgMap[k].Destructor()
+		delete(x.MsgMap, k)
+	}
+	x.NestedTyp = 0
+	if x.NestedMsg != nil {
+		x.NestedMsg.Destructor()
+		x.NestedMsg = nil
+	}
+	for k, _ := range x.NestedTypMap {
+		delete(x.NestedTypMap, k)
+	}
+	for k, _ := range x.NestedMsgMap {
+		x.NestedMsgMap[k].Destructor()
+		delete(x.NestedMsgMap, k)
 	}
-	x.NestedMsgMap = nil
 	if x.TestOneof != nil {
 		if _, ok := x.GetTestOneof().(*Example_OneofMsg); ok {
 			x.GetOneofMsg().Destructor()
 		}
 		x.TestOneof = nil
 	}
+	fastjsonpbExampleUnknown.Reset(x.unknownFields)
 	ExamplePool.Put(x)
 }
 
@@ -1792,7 +3726,7 @@ func (x *Example) IsEmptyFlt64() bool {
 }
 
 func (x *Example) IsEmptyByts() bool {
-	return x.GetByts() == nil
+	return len(x.GetByts()) == 0
 }
 
 func (x *Example) IsEmptyTyp() bool {
@@ -1804,91 +3738,91 @@ func (x *Example) IsEmptyMsg() bool {
 }
 
 func (x *Example) IsEmptyBolArr() bool {
-	return x.GetBolArr() == nil
+	return len(x.GetBolArr()) == 0
 }
 
 func (x *Example) IsEmptyStrArr() bool {
-	return x.GetStrArr() == nil
+	return len(x.GetStrArr()) == 0
 }
 
 func (x *Example) IsEmptyIn32Arr() bool {
-	return x.GetIn32Arr() == nil
+	return len(x.GetIn32Arr()) == 0
 }
 
 func (x *Example) IsEmptyIn64Arr() bool {
-	return x.GetIn64Arr() == nil
+	return len(x.GetIn64Arr()) == 0
 }
 
 func (x *Example) IsEmptyUin32Arr() bool {
-	return x.GetUin32Arr() == nil
+	return len(x.GetUin32Arr()) == 0
 }
 
 func (x *Example) IsEmptyUin64Arr() bool {
-	return x.GetUin64Arr() == nil
+	return len(x.GetUin64Arr()) == 0
 }
 
 func (x *Example) IsEmptyFlt32Arr() bool {
-	return x.GetFlt32Arr() == nil
+	return len(x.GetFlt32Arr()) == 0
 }
 
 func (x *Example) IsEmptyFlt64Arr() bool {
-	return x.GetFlt64Arr() == nil
+	return len(x.GetFlt64Arr()) == 0
 }
 
 func (x *Example) IsEmptyBytsArr() bool {
-	return x.GetBytsArr() == nil
+	return len(x.GetBytsArr()) == 0
 }
 
 func (x *Example) IsEmptyTypArr() bool {
-	return x.GetTypArr() == nil
+	return len(x.GetTypArr()) == 0
 }
 
 func (x *Example) IsEmptyMsgArr() bool {
-	return x.GetMsgArr() == nil
+	return len(x.GetMsgArr()) == 0
 }
 
 func (x *Example) IsEmptyBolMap() bool {
-	return x.GetBolMap() == nil
+	return len(x.GetBolMap()) == 0
 }
 
 func (x *Example) IsEmptyStringMap() bool {
-	return x.GetStringMap() == nil
+	return len(x.GetStringMap()) == 0
 }
 
 func (x *Example) IsEmptyIn32Map() bool {
-	return x.GetIn32Map() == nil
+	return len(x.GetIn32Map()) == 0
 }
 
 func (x *Example) IsEmptyIn64Map() bool {
-	return x.GetIn64Map() == nil
+	return len(x.GetIn64Map()) == 0
 }
 
 func (x *Example) IsEmptyUin32Map() bool {
-	return x.GetUin32Map() == nil
+	return len(x.GetUin32Map()) == 0
 }
 
 func (x *Example) IsEmptyUin64Map() bool {
-	return x.GetUin64Map() == nil
+	return len(x.GetUin64Map()) == 0
 }
 
 func (x *Example) IsEmptyFlt32Map() bool {
-	return x.GetFlt32Map() == nil
+	return len(x.GetFlt32Map()) == 0
 }
 
 func (x *Example) IsEmptyFlt64Map() bool {
-	return x.GetFlt64Map() == nil
+	return len(x.GetFlt64Map()) == 0
 }
 
 func (x *Example) IsEmptyBytsMap() bool {
-	return x.GetBytsMap() == nil
+	return len(x.GetBytsMap()) == 0
 }
 
 func (x *Example) IsEmptyTypMap() bool {
-	return x.GetTypMap() == nil
+	return len(x.GetTypMap()) == 0
 }
 
 func (x *Example) IsEmptyMsgMap() bool {
-	return x.GetMsgMap() == nil
+	return len(x.GetMsgMap()) == 0
 }
 
 func (x *Example) IsEmptyNestedTyp() bool {
@@ -1900,25 +3834,27 @@ func (x *Example) IsEmptyNestedMsg() bool {
 }
 
 func (x *Example) IsEmptyNestedTypMap() bool {
-	return x.GetNestedTypMap() == nil
+	return len(x.GetNestedTypMap()) == 0
 }
 
 func (x *Example) IsEmptyNestedMsgMap() bool {
-	return x.GetNestedMsgMap() == nil
+	return len(x.GetNestedMsgMap()) == 0
 }
 
 func (x *Example_NestedMsg) FastMarshal(buf *buffer.Buffer) {
 	if x == nil {
 		buf.WriteString("{}")
+		return
 	}
 	buf.WriteString("{")
-	if !x.IsEmptyStr() {
+	if !x.IsEmptyStr() || buf.EmitUnpopulated() {
 		buf.WriteStringWithQuote("str")
 		buf.WriteString(":")
 		buf.WriteStringWithQuote(x.GetStr())
 		buf.WriteString(",")
 	}
 
+	fastjsonpbExample_NestedMsgUnknown.Marshal(buf, x.unknownFields)
 	buf.FixSymbol()
 	buf.WriteString("}")
 }
@@ -1927,16 +3863,23 @@ func (x *Example_NestedMsg) FastUnmarshal(p *jsonparser.Parser) {
 	if x == nil {
 		panic("type Example_NestedMsg is nil")
 	}
+	var seen [1]uint64
 	p.Symbol('{')
 	for !p.IsSymbol('}') {
-		key := p.Str()
+		key := p.Key()
 		p.AssertSymbol(':')
 		switch key {
 		case "str":
+			if p.SkipDuplicate(seen[:], 0, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
 			x.Str = p.Str()
 
 		default:
-			p.PassParse()
+			fastjsonpbExample_NestedMsgUnknown.Append(&x.unknownFields, key, p.UnknownValue(key))
 		}
 		p.AssertSymbol(',')
 	}
@@ -1952,12 +3895,68 @@ func Example_NestedMsgNew() *Example_NestedMsg {
 	}
 	return &Example_NestedMsg{}
 }
-func (x *Example_NestedMsg) Destructor() {
-	if x == nil {
-		panic("type Example_NestedMsg is nil")
-	}
-	x.Str = ""
-	Example_NestedMsgPool.Put(x)
+
+var fastjsonpbExample_NestedMsgUnknown jsonparser.UnknownTable
+
+type fastjsonpbExample_NestedMsgSlab struct {
+	msgs []Example_NestedMsg
+	ptrs []*Example_NestedMsg
+}
+
+var fastjsonpbExample_NestedMsgSlabID = arena.NewSlabID()
+
+func fastjsonpbExample_NestedMsgSlabGet(a *arena.Arena) *fastjsonpbExample_NestedMsgSlab {
+	s, _ := a.Slab(fastjsonpbExample_NestedMsgSlabID).(*fastjsonpbExample_NestedMsgSlab)
+	if s == nil {
+		s = &fastjsonpbExample_NestedMsgSlab{}
+		a.SetSlab(fastjsonpbExample_NestedMsgSlabID, s)
+	}
+	return s
+}
+
+func Example_NestedMsgArenaNew(a *arena.Arena) *Example_NestedMsg {
+	if a == nil {
+		return Example_NestedMsgNew()
+	}
+	s := fastjsonpbExample_NestedMsgSlabGet(a)
+	if len(s.msgs) == cap(s.msgs) {
+		s.msgs = make([]Example_NestedMsg, 0, arena.SlabLen(cap(s.msgs)))
+	}
+	s.msgs = s.msgs[:len(s.msgs)+1]
+	return &s.msgs[len(s.msgs)-1]
+}
+
+func Example_NestedMsgArenaAppend(a *arena.Arena, arr []*Example_NestedMsg, v *Example_NestedMsg) []*Example_NestedMsg {
+	if a == nil || len(arr) < cap(arr) {
+		return append(arr, v)
+	}
+	s := fastjsonpbExample_NestedMsgSlabGet(a)
+	n := arena.GrowLen(cap(arr))
+	if cap(s.ptrs)-len(s.ptrs) < n {
+		s.ptrs = make([]*Example_NestedMsg, 0, arena.ChunkLen(cap(s.ptrs), n))
+	}
+	l := len(s.ptrs)
+	s.ptrs = s.ptrs[:l+n]
+	ns := s.ptrs[l : l+len(arr) : l+n]
+	copy(ns, arr)
+	return append(ns, v)
+}
+
+func (x *Example_NestedMsg) FastReset() {
+	if x == nil {
+		panic("type Example_NestedMsg is nil")
+	}
+	x.Str = ""
+	fastjsonpbExample_NestedMsgUnknown.Reset(x.unknownFields)
+}
+
+func (x *Example_NestedMsg) Destructor() {
+	if x == nil {
+		panic("type Example_NestedMsg is nil")
+	}
+	x.Str = ""
+	fastjsonpbExample_NestedMsgUnknown.Reset(x.unknownFields)
+	Example_NestedMsgPool.Put(x)
 }
 
 func (x *Example_NestedMsg) IsEmptyStr() bool {
@@ -1978,22 +3977,965 @@ func (x Example_NestedTyp) GetByStr(s string) Example_NestedTyp {
 	panic("enum Example_NestedTypvalue do not match")
 }
 
-func (x Example_NestedTyp) Set(i int32) {
+// Set 设置为数值i，i不是定义过的值时panic
+func (x *Example_NestedTyp) Set(i int32) {
 	if _, ok := Example_NestedTyp_name[i]; ok {
-		x = Example_NestedTyp(i)
+		*x = Example_NestedTyp(i)
 		return
 	}
 	panic("enum Example_NestedTypvalue do not match")
 }
 
-func (x Example_NestedTyp) SetByStr(s string) {
+// SetByStr 设置为名字s对应的值，s不是定义过的名字时panic
+func (x *Example_NestedTyp) SetByStr(s string) {
 	if i, ok := Example_NestedTyp_value[s]; ok {
-		x = Example_NestedTyp(i)
+		*x = Example_NestedTyp(i)
 		return
 	}
 	panic("enum Example_NestedTypvalue do not match")
 }
 
+func (x *Ordered) FastMarshal(buf *buffer.Buffer) {
+	if x == nil {
+		buf.WriteString("{}")
+		return
+	}
+	if buf.NumberOrder() {
+		x.fastMarshalNumberOrder(buf)
+		return
+	}
+	buf.WriteString("{")
+	if !x.IsEmptyStr() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("str")
+		buf.WriteString(":")
+		buf.WriteStringWithQuote(x.GetStr())
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyIn32Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in32_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("in32Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]int32, 0, len(x.In32Map))
+			for k := range x.In32Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteByte('"')
+				buf.WriteInt32(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.In32Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.In32Map {
+				buf.WriteByte('"')
+				buf.WriteInt32(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.In32Map[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyIn64Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in64_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("in64Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]int64, 0, len(x.In64Map))
+			for k := range x.In64Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteByte('"')
+				buf.WriteInt64(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.In64Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.In64Map {
+				buf.WriteByte('"')
+				buf.WriteInt64(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.In64Map[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyUin32Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin32_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("uin32Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]uint32, 0, len(x.Uin32Map))
+			for k := range x.Uin32Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteByte('"')
+				buf.WriteUint32(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.Uin32Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Uin32Map {
+				buf.WriteByte('"')
+				buf.WriteUint32(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.Uin32Map[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyUin64Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin64_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("uin64Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]uint64, 0, len(x.Uin64Map))
+			for k := range x.Uin64Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteByte('"')
+				buf.WriteUint64(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				x.Uin64Map[k].FastMarshal(buf)
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Uin64Map {
+				buf.WriteByte('"')
+				buf.WriteUint64(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				x.Uin64Map[k].FastMarshal(buf)
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyBolMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("bol_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("bolMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]bool, 0, len(x.BolMap))
+			for k := range x.BolMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return !keys[i] && keys[j] })
+			for _, k := range keys {
+				buf.WriteByte('"')
+				buf.WriteBool(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.BolMap[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.BolMap {
+				buf.WriteByte('"')
+				buf.WriteBool(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.BolMap[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyStringMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("string_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("stringMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.StringMap))
+			for k := range x.StringMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.StringMap[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.StringMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.StringMap[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if x.Choice != nil {
+		if _, ok := x.GetChoice().(*Ordered_OneofStr); ok {
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_str")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofStr")
+				buf.WriteString(":")
+			}
+			buf.WriteStringWithQuote(x.GetOneofStr())
+			buf.WriteString(",")
+
+		} else if _, ok := x.GetChoice().(*Ordered_OneofMsg); ok {
+			if buf.UseProtoNames() {
+				buf.WriteStringWithQuote("oneof_msg")
+				buf.WriteString(":")
+			} else {
+				buf.WriteStringWithQuote("oneofMsg")
+				buf.WriteString(":")
+			}
+			x.GetOneofMsg().FastMarshal(buf)
+			buf.WriteString(",")
+		}
+
+	}
+
+	fastjsonpbOrderedUnknown.Marshal(buf, x.unknownFields)
+	buf.FixSymbol()
+	buf.WriteString("}")
+}
+
+func (x *Ordered) fastMarshalNumberOrder(buf *buffer.Buffer) {
+	buf.WriteString("{")
+	if _, ok := x.GetChoice().(*Ordered_OneofMsg); ok {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("oneof_msg")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("oneofMsg")
+			buf.WriteString(":")
+		}
+		x.GetOneofMsg().FastMarshal(buf)
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyBolMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("bol_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("bolMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]bool, 0, len(x.BolMap))
+			for k := range x.BolMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return !keys[i] && keys[j] })
+			for _, k := range keys {
+				buf.WriteByte('"')
+				buf.WriteBool(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.BolMap[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.BolMap {
+				buf.WriteByte('"')
+				buf.WriteBool(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.BolMap[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyIn32Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in32_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("in32Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]int32, 0, len(x.In32Map))
+			for k := range x.In32Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteByte('"')
+				buf.WriteInt32(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.In32Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.In32Map {
+				buf.WriteByte('"')
+				buf.WriteInt32(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.In32Map[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyStr() || buf.EmitUnpopulated() {
+		buf.WriteStringWithQuote("str")
+		buf.WriteString(":")
+		buf.WriteStringWithQuote(x.GetStr())
+		buf.WriteString(",")
+	}
+
+	if _, ok := x.GetChoice().(*Ordered_OneofStr); ok {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("oneof_str")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("oneofStr")
+			buf.WriteString(":")
+		}
+		buf.WriteStringWithQuote(x.GetOneofStr())
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyStringMap() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("string_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("stringMap")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]string, 0, len(x.StringMap))
+			for k := range x.StringMap {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.StringMap[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.StringMap {
+				buf.WriteStringWithQuote(k)
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.StringMap[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyIn64Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("in64_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("in64Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]int64, 0, len(x.In64Map))
+			for k := range x.In64Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteByte('"')
+				buf.WriteInt64(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.In64Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.In64Map {
+				buf.WriteByte('"')
+				buf.WriteInt64(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.In64Map[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyUin32Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin32_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("uin32Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]uint32, 0, len(x.Uin32Map))
+			for k := range x.Uin32Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteByte('"')
+				buf.WriteUint32(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.Uin32Map[k])
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Uin32Map {
+				buf.WriteByte('"')
+				buf.WriteUint32(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				buf.WriteStringWithQuote(x.Uin32Map[k])
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	if !x.IsEmptyUin64Map() || buf.EmitUnpopulated() {
+		if buf.UseProtoNames() {
+			buf.WriteStringWithQuote("uin64_map")
+			buf.WriteString(":")
+		} else {
+			buf.WriteStringWithQuote("uin64Map")
+			buf.WriteString(":")
+		}
+		buf.WriteString("{")
+		if buf.Deterministic() {
+			keys := make([]uint64, 0, len(x.Uin64Map))
+			for k := range x.Uin64Map {
+				keys = append(keys, k)
+			}
+			sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+			for _, k := range keys {
+				buf.WriteByte('"')
+				buf.WriteUint64(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				x.Uin64Map[k].FastMarshal(buf)
+				buf.WriteString(",")
+			}
+		} else {
+			for k, _ := range x.Uin64Map {
+				buf.WriteByte('"')
+				buf.WriteUint64(k)
+				buf.WriteByte('"')
+				buf.WriteString(":")
+				x.Uin64Map[k].FastMarshal(buf)
+				buf.WriteString(",")
+			}
+		}
+		buf.FixSymbol()
+		buf.WriteString("}")
+		buf.WriteString(",")
+	}
+
+	fastjsonpbOrderedUnknown.Marshal(buf, x.unknownFields)
+	buf.FixSymbol()
+	buf.WriteString("}")
+}
+
+func (x *Ordered) FastUnmarshal(p *jsonparser.Parser) {
+	if x == nil {
+		panic("type Ordered is nil")
+	}
+	var seen [1]uint64
+	p.Symbol('{')
+	for !p.IsSymbol('}') {
+		key := p.Key()
+		p.AssertSymbol(':')
+		switch key {
+		case "str":
+			if p.SkipDuplicate(seen[:], 0, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			x.Str = p.Str()
+
+		case "in32Map", "in32_map":
+			if p.SkipDuplicate(seen[:], 1, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('{')
+			m := x.In32Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[int32]string)
+			}
+			var keys map[int32]struct{}
+			for !p.IsSymbol('}') {
+				key := p.Int32()
+				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(p.Raw()) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[int32]struct{})
+					}
+					keys[key] = struct{}{}
+				}
+				m[key] = p.Str()
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol('}')
+			x.In32Map = m
+
+		case "in64Map", "in64_map":
+			if p.SkipDuplicate(seen[:], 2, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('{')
+			m := x.In64Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[int64]string)
+			}
+			var keys map[int64]struct{}
+			for !p.IsSymbol('}') {
+				key := p.Int64()
+				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(p.Raw()) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[int64]struct{})
+					}
+					keys[key] = struct{}{}
+				}
+				m[key] = p.Str()
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol('}')
+			x.In64Map = m
+
+		case "uin32Map", "uin32_map":
+			if p.SkipDuplicate(seen[:], 3, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('{')
+			m := x.Uin32Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[uint32]string)
+			}
+			var keys map[uint32]struct{}
+			for !p.IsSymbol('}') {
+				key := p.Uint32()
+				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(p.Raw()) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[uint32]struct{})
+					}
+					keys[key] = struct{}{}
+				}
+				m[key] = p.Str()
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol('}')
+			x.Uin32Map = m
+
+		case "uin64Map", "uin64_map":
+			if p.SkipDuplicate(seen[:], 4, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('{')
+			m := x.Uin64Map
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[uint64]*Msg)
+			}
+			var keys map[uint64]struct{}
+			for !p.IsSymbol('}') {
+				key := p.Uint64()
+				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(p.Raw()) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[uint64]struct{})
+					}
+					keys[key] = struct{}{}
+				}
+				tmp := MsgArenaNew(p.Arena())
+				tmp.FastUnmarshal(p)
+				m[key] = tmp
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol('}')
+			x.Uin64Map = m
+
+		case "bolMap", "bol_map":
+			if p.SkipDuplicate(seen[:], 5, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('{')
+			m := x.BolMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[bool]string)
+			}
+			var keys map[bool]struct{}
+			for !p.IsSymbol('}') {
+				key := p.BolKey()
+				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(p.Raw()) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[bool]struct{})
+					}
+					keys[key] = struct{}{}
+				}
+				m[key] = p.Str()
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol('}')
+			x.BolMap = m
+
+		case "stringMap", "string_map":
+			if p.SkipDuplicate(seen[:], 6, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			p.Symbol('{')
+			m := x.StringMap
+			if m == nil || (!p.Merge() && len(m) > 0) {
+				m = make(map[string]string)
+			}
+			var keys map[string]struct{}
+			for !p.IsSymbol('}') {
+				key := p.Str()
+				p.AssertSymbol(':')
+				if p.CheckDuplicate() {
+					if _, ok := keys[key]; ok && p.SkipDuplicateKey(key) {
+						p.AssertSymbol(',')
+						continue
+					}
+					if keys == nil {
+						keys = make(map[string]struct{})
+					}
+					keys[key] = struct{}{}
+				}
+				m[key] = p.Str()
+				p.AssertSymbol(',')
+			}
+			p.AssertSymbol(',')
+			p.Symbol('}')
+			x.StringMap = m
+
+		case "oneofStr", "oneof_str":
+			if p.SkipDuplicate(seen[:], 7, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.Choice.(*Ordered_OneofStr)
+			if !ok {
+				tmp = &Ordered_OneofStr{}
+			}
+			tmp.OneofStr = p.Str()
+			x.Choice = tmp
+		case "oneofMsg", "oneof_msg":
+			if p.SkipDuplicate(seen[:], 7, key) {
+				break
+			}
+			if p.SkipNull() {
+				break
+			}
+			tmp, ok := x.Choice.(*Ordered_OneofMsg)
+			if !ok {
+				tmp = &Ordered_OneofMsg{}
+			}
+			if tmp.OneofMsg != nil {
+				if !p.Merge() {
+					tmp.OneofMsg.FastReset()
+				}
+			} else {
+				tmp.OneofMsg = MsgArenaNew(p.Arena())
+			}
+			tmp.OneofMsg.FastUnmarshal(p)
+			x.Choice = tmp
+		default:
+			fastjsonpbOrderedUnknown.Append(&x.unknownFields, key, p.UnknownValue(key))
+		}
+		p.AssertSymbol(',')
+	}
+	p.AssertSymbol(',')
+	p.Symbol('}')
+}
+
+var OrderedPool sync.Pool
+
+func OrderedNew() *Ordered {
+	if v := OrderedPool.Get(); v != nil {
+		return v.(*Ordered)
+	}
+	return &Ordered{}
+}
+
+var fastjsonpbOrderedUnknown jsonparser.UnknownTable
+
+type fastjsonpbOrderedSlab struct {
+	msgs []Ordered
+	ptrs []*Ordered
+}
+
+var fastjsonpbOrderedSlabID = arena.NewSlabID()
+
+func fastjsonpbOrderedSlabGet(a *arena.Arena) *fastjsonpbOrderedSlab {
+	s, _ := a.Slab(fastjsonpbOrderedSlabID).(*fastjsonpbOrderedSlab)
+	if s == nil {
+		s = &fastjsonpbOrderedSlab{}
+		a.SetSlab(fastjsonpbOrderedSlabID, s)
+	}
+	return s
+}
+
+func OrderedArenaNew(a *arena.Arena) *Ordered {
+	if a == nil {
+		return OrderedNew()
+	}
+	s := fastjsonpbOrderedSlabGet(a)
+	if len(s.msgs) == cap(s.msgs) {
+		s.msgs = make([]Ordered, 0, arena.SlabLen(cap(s.msgs)))
+	}
+	s.msgs = s.msgs[:len(s.msgs)+1]
+	return &s.msgs[len(s.msgs)-1]
+}
+
+func OrderedArenaAppend(a *arena.Arena, arr []*Ordered, v *Ordered) []*Ordered {
+	if a == nil || len(arr) < cap(arr) {
+		return append(arr, v)
+	}
+	s := fastjsonpbOrderedSlabGet(a)
+	n := arena.GrowLen(cap(arr))
+	if cap(s.ptrs)-len(s.ptrs) < n {
+		s.ptrs = make([]*Ordered, 0, arena.ChunkLen(cap(s.ptrs), n))
+	}
+	l := len(s.ptrs)
+	s.ptrs = s.ptrs[:l+n]
+	ns := s.ptrs[l : l+len(arr) : l+n]
+	copy(ns, arr)
+	return append(ns, v)
+}
+
+func (x *Ordered) FastReset() {
+	if x == nil {
+		panic("type Ordered is nil")
+	}
+	x.Str = ""
+	if len(x.In32Map) > 0 {
+		x.In32Map = nil
+	}
+	if len(x.In64Map) > 0 {
+		x.In64Map = nil
+	}
+	if len(x.Uin32Map) > 0 {
+		x.Uin32Map = nil
+	}
+	if len(x.Uin64Map) > 0 {
+		x.Uin64Map = nil
+	}
+	if len(x.BolMap) > 0 {
+		x.BolMap = nil
+	}
+	if len(x.StringMap) > 0 {
+		x.StringMap = nil
+	}
+	if x.Choice != nil {
+		x.Choice = nil
+	}
+	fastjsonpbOrderedUnknown.Reset(x.unknownFields)
+}
+
+func (x *Ordered) Destructor() {
+	if x == nil {
+		panic("type Ordered is nil")
+	}
+	x.Str = ""
+	for k, _ := range x.In32Map {
+		delete(x.In32Map, k)
+	}
+	for k, _ := range x.In64Map {
+		delete(x.In64Map, k)
+	}
+	for k, _ := range x.Uin32Map {
+		delete(x.Uin32Map, k)
+	}
+	for k, _ := range x.Uin64Map {
+		x.Uin64Map[k].Destructor()
+		delete(x.Uin64Map, k)
+	}
+	for k, _ := range x.BolMap {
+		delete(x.BolMap, k)
+	}
+	for k, _ := range x.StringMap {
+		delete(x.StringMap, k)
+	}
+	if x.Choice != nil {
+		if _, ok := x.GetChoice().(*Ordered_OneofMsg); ok {
+			x.GetOneofMsg().Destructor()
+		}
+		x.Choice = nil
+	}
+	fastjsonpbOrderedUnknown.Reset(x.unknownFields)
+	OrderedPool.Put(x)
+}
+
+func (x *Ordered) IsEmptyStr() bool {
+	return x.GetStr() == ""
+}
+
+func (x *Ordered) IsEmptyIn32Map() bool {
+	return len(x.GetIn32Map()) == 0
+}
+
+func (x *Ordered) IsEmptyIn64Map() bool {
+	return len(x.GetIn64Map()) == 0
+}
+
+func (x *Ordered) IsEmptyUin32Map() bool {
+	return len(x.GetUin32Map()) == 0
+}
+
+func (x *Ordered) IsEmptyUin64Map() bool {
+	return len(x.GetUin64Map()) == 0
+}
+
+func (x *Ordered) IsEmptyBolMap() bool {
+	return len(x.GetBolMap()) == 0
+}
+
+func (x *Ordered) IsEmptyStringMap() bool {
+	return len(x.GetStringMap()) == 0
+}
+
 func (x Typ) Get(i int32) Typ {
 	if _, ok := Typ_name[i]; ok {
 		return Typ(i)
@@ -2008,18 +4950,27 @@ func (x Typ) GetByStr(s string) Typ {
 	panic("enum Typvalue do not match")
 }
 
-func (x Typ) Set(i int32) {
+// Set 设置为数值i，i不是定义过的值时panic
+func (x *Typ) Set(i int32) {
 	if _, ok := Typ_name[i]; ok {
-		x = Typ(i)
+		*x = Typ(i)
 		return
 	}
 	panic("enum Typvalue do not match")
 }
 
-func (x Typ) SetByStr(s string) {
+// SetByStr 设置为名字s对应的值，s不是定义过的名字时panic
+func (x *Typ) SetByStr(s string) {
 	if i, ok := Typ_value[s]; ok {
-		x = Typ(i)
+		*x = Typ(i)
 		return
 	}
 	panic("enum Typvalue do not match")
 }
+
+func init() {
+	json.RegisterType("example.Msg", func() proto.Message { return MsgNew() }, func(m proto.Message) { m.(*Msg).Destructor() })
+	json.RegisterType("example.Example", func() proto.Message { return ExampleNew() }, func(m proto.Message) { m.(*Example).Destructor() })
+	json.RegisterType("example.Example.NestedMsg", func() proto.Message { return Example_NestedMsgNew() }, func(m proto.Message) { m.(*Example_NestedMsg).Destructor() })
+	json.RegisterType("example.Ordered", func() proto.Message { return OrderedNew() }, func(m proto.Message) { m.(*Ordered).Destructor() })
+}
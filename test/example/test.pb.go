@@ -910,6 +910,143 @@ func (*Example_OneofByts) isExample_TestOneof() {}
 
 func (*Example_OneofMsg) isExample_TestOneof() {}
 
+// 声明顺序与字段号不同，测试字段顺序以及非string类型的map key
+type Ordered struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Choice:
+	//	*Ordered_OneofStr
+	//	*Ordered_OneofMsg
+	Choice    isOrdered_Choice  `protobuf_oneof:"choice"`
+	Str       string            `protobuf:"bytes,4,opt,name=str,proto3" json:"str,omitempty"`
+	In32Map   map[int32]string  `protobuf:"bytes,3,rep,name=in32_map,json=in32Map,proto3" json:"in32_map,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	In64Map   map[int64]string  `protobuf:"bytes,7,rep,name=in64_map,json=in64Map,proto3" json:"in64_map,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Uin32Map  map[uint32]string `protobuf:"bytes,8,rep,name=uin32_map,json=uin32Map,proto3" json:"uin32_map,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Uin64Map  map[uint64]*Msg   `protobuf:"bytes,9,rep,name=uin64_map,json=uin64Map,proto3" json:"uin64_map,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	BolMap    map[bool]string   `protobuf:"bytes,2,rep,name=bol_map,json=bolMap,proto3" json:"bol_map,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	StringMap map[string]string `protobuf:"bytes,6,rep,name=string_map,json=stringMap,proto3" json:"string_map,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Ordered) Reset() {
+	*x = Ordered{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_test_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ordered) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ordered) ProtoMessage() {}
+
+func (x *Ordered) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ordered.ProtoReflect.Descriptor instead.
+func (*Ordered) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{2}
+}
+
+func (m *Ordered) GetChoice() isOrdered_Choice {
+	if m != nil {
+		return m.Choice
+	}
+	return nil
+}
+
+func (x *Ordered) GetOneofStr() string {
+	if x, ok := x.GetChoice().(*Ordered_OneofStr); ok {
+		return x.OneofStr
+	}
+	return ""
+}
+
+func (x *Ordered) GetOneofMsg() *Msg {
+	if x, ok := x.GetChoice().(*Ordered_OneofMsg); ok {
+		return x.OneofMsg
+	}
+	return nil
+}
+
+func (x *Ordered) GetStr() string {
+	if x != nil {
+		return x.Str
+	}
+	return ""
+}
+
+func (x *Ordered) GetIn32Map() map[int32]string {
+	if x != nil {
+		return x.In32Map
+	}
+	return nil
+}
+
+func (x *Ordered) GetIn64Map() map[int64]string {
+	if x != nil {
+		return x.In64Map
+	}
+	return nil
+}
+
+func (x *Ordered) GetUin32Map() map[uint32]string {
+	if x != nil {
+		return x.Uin32Map
+	}
+	return nil
+}
+
+func (x *Ordered) GetUin64Map() map[uint64]*Msg {
+	if x != nil {
+		return x.Uin64Map
+	}
+	return nil
+}
+
+func (x *Ordered) GetBolMap() map[bool]string {
+	if x != nil {
+		return x.BolMap
+	}
+	return nil
+}
+
+func (x *Ordered) GetStringMap() map[string]string {
+	if x != nil {
+		return x.StringMap
+	}
+	return nil
+}
+
+type isOrdered_Choice interface {
+	isOrdered_Choice()
+}
+
+type Ordered_OneofStr struct {
+	OneofStr string `protobuf:"bytes,5,opt,name=oneof_str,json=oneofStr,proto3,oneof"`
+}
+
+type Ordered_OneofMsg struct {
+	OneofMsg *Msg `protobuf:"bytes,1,opt,name=oneof_msg,json=oneofMsg,proto3,oneof"`
+}
+
+func (*Ordered_OneofStr) isOrdered_Choice() {}
+
+func (*Ordered_OneofMsg) isOrdered_Choice() {}
+
 type Example_NestedMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -921,7 +1058,7 @@ type Example_NestedMsg struct {
 func (x *Example_NestedMsg) Reset() {
 	*x = Example_NestedMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_test_proto_msgTypes[11]
+		mi := &file_test_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -934,7 +1071,7 @@ func (x *Example_NestedMsg) String() string {
 func (*Example_NestedMsg) ProtoMessage() {}
 
 func (x *Example_NestedMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_test_proto_msgTypes[11]
+	mi := &file_test_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1232,11 +1369,64 @@ var file_test_proto_rawDesc = []byte{
 	0x2c, 0x0a, 0x09, 0x4e, 0x65, 0x73, 0x74, 0x65, 0x64, 0x54, 0x79, 0x70, 0x12, 0x0b, 0x0a, 0x07,
 	0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x59, 0x50,
 	0x41, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x59, 0x50, 0x42, 0x10, 0x02, 0x42, 0x0c, 0x0a,
-	0x0a, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x2a, 0x26, 0x0a, 0x03, 0x54,
-	0x79, 0x70, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12,
-	0x08, 0x0a, 0x04, 0x54, 0x59, 0x50, 0x41, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x59, 0x50,
-	0x42, 0x10, 0x02, 0x42, 0x0e, 0x5a, 0x0c, 0x74, 0x65, 0x73, 0x74, 0x2f, 0x65, 0x78, 0x61, 0x6d,
-	0x70, 0x6c, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x0a, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x22, 0xcf, 0x06, 0x0a, 0x07,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x09, 0x6f, 0x6e, 0x65, 0x6f, 0x66,
+	0x5f, 0x73, 0x74, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x6f, 0x6e,
+	0x65, 0x6f, 0x66, 0x53, 0x74, 0x72, 0x12, 0x2b, 0x0a, 0x09, 0x6f, 0x6e, 0x65, 0x6f, 0x66, 0x5f,
+	0x6d, 0x73, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x78, 0x61, 0x6d,
+	0x70, 0x6c, 0x65, 0x2e, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x08, 0x6f, 0x6e, 0x65, 0x6f, 0x66,
+	0x4d, 0x73, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x74, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x73, 0x74, 0x72, 0x12, 0x38, 0x0a, 0x08, 0x69, 0x6e, 0x33, 0x32, 0x5f, 0x6d, 0x61,
+	0x70, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x2e, 0x49, 0x6e, 0x33, 0x32, 0x4d, 0x61,
+	0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x69, 0x6e, 0x33, 0x32, 0x4d, 0x61, 0x70, 0x12,
+	0x38, 0x0a, 0x08, 0x69, 0x6e, 0x36, 0x34, 0x5f, 0x6d, 0x61, 0x70, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x4f, 0x72, 0x64, 0x65,
+	0x72, 0x65, 0x64, 0x2e, 0x49, 0x6e, 0x36, 0x34, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x07, 0x69, 0x6e, 0x36, 0x34, 0x4d, 0x61, 0x70, 0x12, 0x3b, 0x0a, 0x09, 0x75, 0x69, 0x6e,
+	0x33, 0x32, 0x5f, 0x6d, 0x61, 0x70, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x65,
+	0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x2e, 0x55,
+	0x69, 0x6e, 0x33, 0x32, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x75, 0x69,
+	0x6e, 0x33, 0x32, 0x4d, 0x61, 0x70, 0x12, 0x3b, 0x0a, 0x09, 0x75, 0x69, 0x6e, 0x36, 0x34, 0x5f,
+	0x6d, 0x61, 0x70, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x65, 0x78, 0x61, 0x6d,
+	0x70, 0x6c, 0x65, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x2e, 0x55, 0x69, 0x6e, 0x36,
+	0x34, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x75, 0x69, 0x6e, 0x36, 0x34,
+	0x4d, 0x61, 0x70, 0x12, 0x35, 0x0a, 0x07, 0x62, 0x6f, 0x6c, 0x5f, 0x6d, 0x61, 0x70, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x4f,
+	0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x2e, 0x42, 0x6f, 0x6c, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x06, 0x62, 0x6f, 0x6c, 0x4d, 0x61, 0x70, 0x12, 0x3e, 0x0a, 0x0a, 0x73, 0x74,
+	0x72, 0x69, 0x6e, 0x67, 0x5f, 0x6d, 0x61, 0x70, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f,
+	0x2e, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x64,
+	0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x09, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x70, 0x1a, 0x3a, 0x0a, 0x0c, 0x49, 0x6e,
+	0x33, 0x32, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3a, 0x0a, 0x0c, 0x49, 0x6e, 0x36, 0x34, 0x4d, 0x61,
+	0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x1a, 0x3b, 0x0a, 0x0d, 0x55, 0x69, 0x6e, 0x33, 0x32, 0x4d, 0x61, 0x70, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a,
+	0x49, 0x0a, 0x0d, 0x55, 0x69, 0x6e, 0x36, 0x34, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x22, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x4d, 0x73, 0x67, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x42, 0x6f,
+	0x6c, 0x4d, 0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3c, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d,
+	0x61, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x2a, 0x26, 0x0a,
+	0x03, 0x54, 0x79, 0x70, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10,
+	0x00, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x59, 0x50, 0x41, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x54,
+	0x59, 0x50, 0x42, 0x10, 0x02, 0x42, 0x0e, 0x5a, 0x0c, 0x74, 0x65, 0x73, 0x74, 0x2f, 0x65, 0x78,
+	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1252,75 +1442,90 @@ func file_test_proto_rawDescGZIP() []byte {
 }
 
 var file_test_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_test_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_test_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_test_proto_goTypes = []interface{}{
 	(Typ)(0),                  // 0: example.Typ
 	(Example_NestedTyp)(0),    // 1: example.Example.NestedTyp
 	(*Msg)(nil),               // 2: example.Msg
 	(*Example)(nil),           // 3: example.Example
-	nil,                       // 4: example.Msg.BolMapEntry
-	nil,                       // 5: example.Msg.StringMapEntry
-	nil,                       // 6: example.Msg.In32MapEntry
-	nil,                       // 7: example.Msg.In64MapEntry
-	nil,                       // 8: example.Msg.Uin32MapEntry
-	nil,                       // 9: example.Msg.Uin64MapEntry
-	nil,                       // 10: example.Msg.Flt32MapEntry
-	nil,                       // 11: example.Msg.Flt64MapEntry
-	nil,                       // 12: example.Msg.BytsMapEntry
-	(*Example_NestedMsg)(nil), // 13: example.Example.NestedMsg
-	nil,                       // 14: example.Example.BolMapEntry
-	nil,                       // 15: example.Example.StringMapEntry
-	nil,                       // 16: example.Example.In32MapEntry
-	nil,                       // 17: example.Example.In64MapEntry
-	nil,                       // 18: example.Example.Uin32MapEntry
-	nil,                       // 19: example.Example.Uin64MapEntry
-	nil,                       // 20: example.Example.Flt32MapEntry
-	nil,                       // 21: example.Example.Flt64MapEntry
-	nil,                       // 22: example.Example.BytsMapEntry
-	nil,                       // 23: example.Example.TypMapEntry
-	nil,                       // 24: example.Example.MsgMapEntry
-	nil,                       // 25: example.Example.NestedTypMapEntry
-	nil,                       // 26: example.Example.NestedMsgMapEntry
+	(*Ordered)(nil),           // 4: example.Ordered
+	nil,                       // 5: example.Msg.BolMapEntry
+	nil,                       // 6: example.Msg.StringMapEntry
+	nil,                       // 7: example.Msg.In32MapEntry
+	nil,                       // 8: example.Msg.In64MapEntry
+	nil,                       // 9: example.Msg.Uin32MapEntry
+	nil,                       // 10: example.Msg.Uin64MapEntry
+	nil,                       // 11: example.Msg.Flt32MapEntry
+	nil,                       // 12: example.Msg.Flt64MapEntry
+	nil,                       // 13: example.Msg.BytsMapEntry
+	(*Example_NestedMsg)(nil), // 14: example.Example.NestedMsg
+	nil,                       // 15: example.Example.BolMapEntry
+	nil,                       // 16: example.Example.StringMapEntry
+	nil,                       // 17: example.Example.In32MapEntry
+	nil,                       // 18: example.Example.In64MapEntry
+	nil,                       // 19: example.Example.Uin32MapEntry
+	nil,                       // 20: example.Example.Uin64MapEntry
+	nil,                       // 21: example.Example.Flt32MapEntry
+	nil,                       // 22: example.Example.Flt64MapEntry
+	nil,                       // 23: example.Example.BytsMapEntry
+	nil,                       // 24: example.Example.TypMapEntry
+	nil,                       // 25: example.Example.MsgMapEntry
+	nil,                       // 26: example.Example.NestedTypMapEntry
+	nil,                       // 27: example.Example.NestedMsgMapEntry
+	nil,                       // 28: example.Ordered.In32MapEntry
+	nil,                       // 29: example.Ordered.In64MapEntry
+	nil,                       // 30: example.Ordered.Uin32MapEntry
+	nil,                       // 31: example.Ordered.Uin64MapEntry
+	nil,                       // 32: example.Ordered.BolMapEntry
+	nil,                       // 33: example.Ordered.StringMapEntry
 }
 var file_test_proto_depIdxs = []int32{
-	4,  // 0: example.Msg.bol_map:type_name -> example.Msg.BolMapEntry
-	5,  // 1: example.Msg.string_map:type_name -> example.Msg.StringMapEntry
-	6,  // 2: example.Msg.in32_map:type_name -> example.Msg.In32MapEntry
-	7,  // 3: example.Msg.in64_map:type_name -> example.Msg.In64MapEntry
-	8,  // 4: example.Msg.uin32_map:type_name -> example.Msg.Uin32MapEntry
-	9,  // 5: example.Msg.uin64_map:type_name -> example.Msg.Uin64MapEntry
-	10, // 6: example.Msg.flt32_map:type_name -> example.Msg.Flt32MapEntry
-	11, // 7: example.Msg.flt64_map:type_name -> example.Msg.Flt64MapEntry
-	12, // 8: example.Msg.byts_map:type_name -> example.Msg.BytsMapEntry
+	5,  // 0: example.Msg.bol_map:type_name -> example.Msg.BolMapEntry
+	6,  // 1: example.Msg.string_map:type_name -> example.Msg.StringMapEntry
+	7,  // 2: example.Msg.in32_map:type_name -> example.Msg.In32MapEntry
+	8,  // 3: example.Msg.in64_map:type_name -> example.Msg.In64MapEntry
+	9,  // 4: example.Msg.uin32_map:type_name -> example.Msg.Uin32MapEntry
+	10, // 5: example.Msg.uin64_map:type_name -> example.Msg.Uin64MapEntry
+	11, // 6: example.Msg.flt32_map:type_name -> example.Msg.Flt32MapEntry
+	12, // 7: example.Msg.flt64_map:type_name -> example.Msg.Flt64MapEntry
+	13, // 8: example.Msg.byts_map:type_name -> example.Msg.BytsMapEntry
 	0,  // 9: example.Example.typ:type_name -> example.Typ
 	2,  // 10: example.Example.msg:type_name -> example.Msg
 	0,  // 11: example.Example.typ_arr:type_name -> example.Typ
 	2,  // 12: example.Example.msg_arr:type_name -> example.Msg
-	14, // 13: example.Example.bol_map:type_name -> example.Example.BolMapEntry
-	15, // 14: example.Example.string_map:type_name -> example.Example.StringMapEntry
-	16, // 15: example.Example.in32_map:type_name -> example.Example.In32MapEntry
-	17, // 16: example.Example.in64_map:type_name -> example.Example.In64MapEntry
-	18, // 17: example.Example.uin32_map:type_name -> example.Example.Uin32MapEntry
-	19, // 18: example.Example.uin64_map:type_name -> example.Example.Uin64MapEntry
-	20, // 19: example.Example.flt32_map:type_name -> example.Example.Flt32MapEntry
-	21, // 20: example.Example.flt64_map:type_name -> example.Example.Flt64MapEntry
-	22, // 21: example.Example.byts_map:type_name -> example.Example.BytsMapEntry
-	23, // 22: example.Example.typ_map:type_name -> example.Example.TypMapEntry
-	24, // 23: example.Example.msg_map:type_name -> example.Example.MsgMapEntry
+	15, // 13: example.Example.bol_map:type_name -> example.Example.BolMapEntry
+	16, // 14: example.Example.string_map:type_name -> example.Example.StringMapEntry
+	17, // 15: example.Example.in32_map:type_name -> example.Example.In32MapEntry
+	18, // 16: example.Example.in64_map:type_name -> example.Example.In64MapEntry
+	19, // 17: example.Example.uin32_map:type_name -> example.Example.Uin32MapEntry
+	20, // 18: example.Example.uin64_map:type_name -> example.Example.Uin64MapEntry
+	21, // 19: example.Example.flt32_map:type_name -> example.Example.Flt32MapEntry
+	22, // 20: example.Example.flt64_map:type_name -> example.Example.Flt64MapEntry
+	23, // 21: example.Example.byts_map:type_name -> example.Example.BytsMapEntry
+	24, // 22: example.Example.typ_map:type_name -> example.Example.TypMapEntry
+	25, // 23: example.Example.msg_map:type_name -> example.Example.MsgMapEntry
 	1,  // 24: example.Example.nested_typ:type_name -> example.Example.NestedTyp
-	13, // 25: example.Example.nested_msg:type_name -> example.Example.NestedMsg
-	25, // 26: example.Example.nested_typ_map:type_name -> example.Example.NestedTypMapEntry
-	26, // 27: example.Example.nested_msg_map:type_name -> example.Example.NestedMsgMapEntry
+	14, // 25: example.Example.nested_msg:type_name -> example.Example.NestedMsg
+	26, // 26: example.Example.nested_typ_map:type_name -> example.Example.NestedTypMapEntry
+	27, // 27: example.Example.nested_msg_map:type_name -> example.Example.NestedMsgMapEntry
 	2,  // 28: example.Example.oneof_msg:type_name -> example.Msg
-	0,  // 29: example.Example.TypMapEntry.value:type_name -> example.Typ
-	2,  // 30: example.Example.MsgMapEntry.value:type_name -> example.Msg
-	1,  // 31: example.Example.NestedTypMapEntry.value:type_name -> example.Example.NestedTyp
-	13, // 32: example.Example.NestedMsgMapEntry.value:type_name -> example.Example.NestedMsg
-	33, // [33:33] is the sub-list for method output_type
-	33, // [33:33] is the sub-list for method input_type
-	33, // [33:33] is the sub-list for extension type_name
-	33, // [33:33] is the sub-list for extension extendee
-	0,  // [0:33] is the sub-list for field type_name
+	2,  // 29: example.Ordered.oneof_msg:type_name -> example.Msg
+	28, // 30: example.Ordered.in32_map:type_name -> example.Ordered.In32MapEntry
+	29, // 31: example.Ordered.in64_map:type_name -> example.Ordered.In64MapEntry
+	30, // 32: example.Ordered.uin32_map:type_name -> example.Ordered.Uin32MapEntry
+	31, // 33: example.Ordered.uin64_map:type_name -> example.Ordered.Uin64MapEntry
+	32, // 34: example.Ordered.bol_map:type_name -> example.Ordered.BolMapEntry
+	33, // 35: example.Ordered.string_map:type_name -> example.Ordered.StringMapEntry
+	0,  // 36: example.Example.TypMapEntry.value:type_name -> example.Typ
+	2,  // 37: example.Example.MsgMapEntry.value:type_name -> example.Msg
+	1,  // 38: example.Example.NestedTypMapEntry.value:type_name -> example.Example.NestedTyp
+	14, // 39: example.Example.NestedMsgMapEntry.value:type_name -> example.Example.NestedMsg
+	2,  // 40: example.Ordered.Uin64MapEntry.value:type_name -> example.Msg
+	41, // [41:41] is the sub-list for method output_type
+	41, // [41:41] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
 }
 
 func init() { file_test_proto_init() }
@@ -1353,7 +1558,19 @@ func file_test_proto_init() {
 				return nil
 			}
 		}
-		file_test_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_test_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ordered); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_test_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Example_NestedMsg); i {
 			case 0:
 				return &v.state
@@ -1381,13 +1598,17 @@ func file_test_proto_init() {
 		(*Example_OneofByts)(nil),
 		(*Example_OneofMsg)(nil),
 	}
+	file_test_proto_msgTypes[2].OneofWrappers = []interface{}{
+		(*Ordered_OneofStr)(nil),
+		(*Ordered_OneofMsg)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_test_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   25,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
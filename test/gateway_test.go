@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/gateway"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/test/example"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/test/example/embed"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// 解析为interface{}比较，忽略字段顺序和空白
+func jsonEqual(t *testing.T, a, b []byte) bool {
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatalf("%v: %s", err, a)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatalf("%v: %s", err, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func TestGatewayMarshaler(t *testing.T) {
+	special := &example.Msg{In64: math.MaxInt64, Uin64: math.MaxUint64, Flt32: float32(math.Inf(-1)), Flt64: math.NaN()}
+	tiny := &example.Msg{Flt32: 1e-7, Flt64: 1e21}
+	item := &embed.Item{Name: "n", Id: 1 << 60, DisplayName: "d", Items: []*embed.Item{{}}, Value: &embed.Item_Item{Item: &embed.Item{}}}
+	msgs := []proto.Message{msg, e, orderedMsg(), special, tiny, item, &embed.Item{}}
+	for _, opts := range []protojson.MarshalOptions{
+		{},
+		{UseProtoNames: true},
+		{UseEnumNumbers: true},
+		{EmitUnpopulated: true},
+		{Multiline: true},
+		{Indent: "\t", UseProtoNames: true, UseEnumNumbers: true, EmitUnpopulated: true},
+	} {
+		fast := &gateway.JSONPb{MarshalOptions: opts}
+		std := &runtime.JSONPb{MarshalOptions: opts}
+		for _, m := range msgs {
+			got, err := fast.Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := std.Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !jsonEqual(t, got, want) {
+				t.Fatalf("%+v %T:\n%s\nwant\n%s", opts, m, got, want)
+			}
+			if len(got) > 2 && (opts.Multiline || opts.Indent != "") != bytes.Contains(got, []byte("\n")) {
+				t.Fatalf("%+v: %s", opts, got)
+			}
+			back := proto.Clone(m)
+			if err := fast.Unmarshal(got, back); err != nil || !proto.Equal(back, m) && m != special {
+				t.Fatalf("%+v %T: %v %s", opts, m, err, got)
+			}
+		}
+	}
+}
+
+func TestGatewayStream(t *testing.T) {
+	var out bytes.Buffer
+	m := &gateway.JSONPb{UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true}}
+	enc := m.NewEncoder(&out)
+	// 没有生成代码的message及非message类型交给runtime.JSONPb
+	for _, v := range []interface{}{msg, wrapperspb.String("s"), "str", orderedMsg()} {
+		if err := enc.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if bytes.Count(out.Bytes(), m.Delimiter()) != 4 {
+		t.Fatalf("%s", out.Bytes())
+	}
+	out.WriteString(`{"str":"x","unknown":1}`)
+
+	dec := m.NewDecoder(&out)
+	gotMsg, gotStr, gotOrdered := &example.Msg{}, &wrapperspb.StringValue{}, &example.Ordered{}
+	var str string
+	for _, v := range []interface{}{gotMsg, gotStr, &str, gotOrdered} {
+		if err := dec.Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !proto.Equal(gotMsg, msg) || gotStr.GetValue() != "s" || str != "str" || !proto.Equal(gotOrdered, orderedMsg()) {
+		t.Fatalf("%v %v %q %v", gotMsg, gotStr, str, gotOrdered)
+	}
+	last := &example.Msg{}
+	if err := dec.Decode(last); err != nil || last.GetStr() != "x" {
+		t.Fatalf("%v %v", err, last)
+	}
+	if m.ContentType(msg) != "application/json" {
+		t.Fatal(m.ContentType(msg))
+	}
+}
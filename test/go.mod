@@ -3,15 +3,27 @@ module github.com/superjsf2010/protoc-gen-fastjsonpb/test
 go 1.17
 
 require (
+	github.com/golang/protobuf v1.5.2
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.10.3
+	github.com/json-iterator/go v1.1.12
 	github.com/superjsf2010/protoc-gen-fastjsonpb v1.0.0
-	google.golang.org/protobuf v1.26.0
+	github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/gateway v1.0.0
+	github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/grpccodec v1.0.0
+	google.golang.org/grpc v1.47.0
+	google.golang.org/protobuf v1.28.0
 )
 
 require (
-	github.com/golang/protobuf v1.5.2 // indirect
-	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
+	golang.org/x/net v0.0.0-20220127200216-cd36cc0744dd // indirect
+	golang.org/x/sys v0.0.0-20211216021012-1d35b9e2eb4e // indirect
+	golang.org/x/text v0.3.7 // indirect
+	google.golang.org/genproto v0.0.0-20220519153652-3a47de7e79bd // indirect
 )
 
-replace github.com/superjsf2010/protoc-gen-fastjsonpb => ../
+replace (
+	github.com/superjsf2010/protoc-gen-fastjsonpb => ../
+	github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/gateway => ../encoding/gateway
+	github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/grpccodec => ../encoding/grpccodec
+)
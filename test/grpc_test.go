@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/grpccodec"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/test/example"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// 原样返回请求，同时检查请求使用的content-type
+func echoHandler(newReq func() proto.Message) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+		req := newReq()
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		if ct := md.Get("content-type"); len(ct) != 1 || ct[0] != "application/grpc+json" {
+			return nil, status.Errorf(codes.InvalidArgument, "content-type %v", ct)
+		}
+		return req, nil
+	}
+}
+
+func TestGRPCCodec(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "example.Echo",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "Msg", Handler: echoHandler(func() proto.Message { return &example.Msg{} })},
+			{MethodName: "String", Handler: echoHandler(func() proto.Message { return &wrapperspb.StringValue{} })},
+		},
+	}, struct{}{})
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype("json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	resp := &example.Msg{}
+	if err := conn.Invoke(context.Background(), "/example.Echo/Msg", msg, resp); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(resp, msg) {
+		t.Fatalf("got %v, want %v", resp, msg)
+	}
+	// 没有生成代码的message使用protojson
+	str := &wrapperspb.StringValue{}
+	if err := conn.Invoke(context.Background(), "/example.Echo/String", wrapperspb.String("s"), str); err != nil {
+		t.Fatal(err)
+	}
+	if str.GetValue() != "s" {
+		t.Fatalf("got %v", str)
+	}
+}
+
+// 生成代码与protojson的输出格式一致：超过2^53的64位整数、NaN输出为字符串；忽略未知字段
+func TestGRPCCodecFormat(t *testing.T) {
+	codec := encoding.GetCodec(grpccodec.Name)
+	m := &example.Msg{In64: 1<<53 + 1, Uin64: math.MaxUint64, Flt64: math.NaN()}
+	ret, err := codec.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := protojson.Marshal(m)
+	if !jsonEqual(t, ret, want) {
+		t.Fatalf("%s, want %s", ret, want)
+	}
+	got := &example.Msg{}
+	if err := codec.Unmarshal(ret, got); err != nil || got.In64 != m.In64 || got.Uin64 != m.Uin64 || !math.IsNaN(got.Flt64) {
+		t.Fatalf("%v %v", err, got)
+	}
+	if err := codec.Unmarshal([]byte(`{"str":null,"in64":"9007199254740993","future":1}`), got); err != nil || got.In64 != 1<<53+1 {
+		t.Fatalf("%v %v", err, got)
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	json "encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	fastjsonpb "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/json"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/test/example"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/jsonparser"
+)
+
+// 与encoding/json.Valid对比的测试数据
+var validCorpus = []string{
+	// 合法
+	`{}`, `[]`, `{ }`, `[ ]`, " \t\r\n{}\n", `{ "a" : 1 }`, "{\"a\":1}\n",
+	`0`, `-0`, `1`, `-1`, `123`, `1.5`, `-1.5e10`, `1e5`, `1E+5`, `1e-5`, `0.0`, `0e0`,
+	`true`, `false`, `null`, `""`, `"abc"`, `"\"\\\/\b\f\n\r\t"`, `"é"`, `"😀"`, `"\uDEAD"`,
+	`"\u00e9\uD83D\uDE00"`, `"\uD83D"`, `"\uD83Dx"`, `"\uD83D\u0041"`,
+	`[1,2,3]`, `[ 1 , 2 , 3 ]`, `{"a":[1,{"b":null}],"c":{"d":[]}}`, `[[[[[]]]]]`, `"é"`,
+	`{"a":1,"a":2}`, `[true,false,null]`, `["a",{"b":"c"}]`,
+	// 非法
+	``, ` `, `{`, `}`, `[`, `]`, `{]`, `[}`, `{"a"}`, `{"a":}`, `{"a" 1}`, `{"a":1,}`, `[1,]`, `[,1]`, `{,}`,
+	`{"a":1 "b":2}`, `[1 2]`, `{1:2}`, `{"a":1}}`, `[1]]`, `{} {}`, `{}x`, `1 2`, `[1]x`,
+	`01`, `-`, `-01`, `1.`, `.5`, `1e`, `1e+`, `+1`, `0x10`, `1.e5`, `Infinity`, `NaN`, `-Infinity`,
+	`tru`, `truex`, `t`, `fals`, `nul`, `nulll`, `True`, `NULL`,
+	`"abc`, `"\"`, `"\x"`, `"\u12"`, `"\u12G4"`, `"a` + "\n" + `b"`, "\"\t\"", `'a'`, `"\'"`,
+	`{"a":1]`, `[1}`, `{"a":{"b":1}`, `[[1]`, `{"a":[}`,
+}
+
+func TestValidCorpus(t *testing.T) {
+	for _, c := range validCorpus {
+		if got, want := jsonparser.Valid([]byte(c)), json.Valid([]byte(c)); got != want {
+			t.Errorf("Valid(%q) = %v, encoding/json = %v", c, got, want)
+		}
+	}
+}
+
+// 对合法数据随机修改后与encoding/json.Valid对比
+func TestValidMutations(t *testing.T) {
+	seeds := []string{
+		`{"str":"s\"t","float":-10.123e-2,"nested":{"arr":[1,2,{"a":null}]},"bol":true,"bol1":false,"empty":{},"e":[]}`,
+		`[0,-0.5,1E3,"é\n",[[]],{"":""}]`,
+	}
+	alphabet := []byte("{}[]:,\"\\ \t\n0123456789-+.eEtrufalsn\x01")
+	r := rand.New(rand.NewSource(1))
+	for _, seed := range seeds {
+		for i := 0; i < 20000; i++ {
+			b := []byte(seed)
+			for n := r.Intn(3) + 1; n > 0; n-- {
+				pos := r.Intn(len(b))
+				switch r.Intn(3) {
+				case 0:
+					b[pos] = alphabet[r.Intn(len(alphabet))]
+				case 1:
+					b = append(b[:pos], b[pos+1:]...)
+				default:
+					b = append(b[:pos], append([]byte{alphabet[r.Intn(len(alphabet))]}, b[pos:]...)...)
+				}
+			}
+			if got, want := jsonparser.Valid(b), json.Valid(b); got != want {
+				t.Fatalf("Valid(%q) = %v, encoding/json = %v", b, got, want)
+			}
+		}
+	}
+}
+
+func TestValidDepth(t *testing.T) {
+	for _, n := range []int{10000, 10001} {
+		b := []byte(strings.Repeat("[", n) + strings.Repeat("]", n))
+		if got, want := jsonparser.Valid(b), json.Valid(b); got != want {
+			t.Errorf("Valid(depth %d) = %v, encoding/json = %v", n, got, want)
+		}
+	}
+}
+
+func TestUnmarshalWhitespace(t *testing.T) {
+	data := []byte("{\n  \"str\" : \"s\" ,\n  \"in32Arr\" : [ 1 , 2 ] ,\n  \"stringMap\" : { } ,\n  \"msg\" : { \"in32\" : 1 }\n}\n")
+	m := &example.Example{}
+	if err := fastjsonpb.Unmarshal(data, m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Str != "s" || len(m.In32Arr) != 2 || m.Msg.In32 != 1 {
+		t.Errorf("unexpected result: %v", m)
+	}
+	if err := fastjsonpb.Unmarshal([]byte(`{"str":"s"} {}`), &example.Example{}); err == nil {
+		t.Errorf("trailing data should be rejected")
+	}
+}
+
+func TestUnicodeEscape(t *testing.T) {
+	cases := []string{
+		`"\u0041\u00e9\u4e2d"`,
+		`"\uD83D\uDE00"`,
+		`"\uD83D"`,
+		`"\uDE00\uD83D"`,
+		`"\uD83D\u0041"`,
+		`"\uD83D\n"`,
+		`"a\u0000b"`,
+		`"\u2028\\\/"`,
+	}
+	for _, c := range cases {
+		var want string
+		if err := json.Unmarshal([]byte(c), &want); err != nil {
+			t.Fatal(err)
+		}
+		m := &example.Example{}
+		if err := fastjsonpb.Unmarshal([]byte(`{"str":`+c+`}`), m); err != nil {
+			t.Fatalf("%s: %v", c, err)
+		}
+		if m.Str != want {
+			t.Errorf("%s: got %q, want %q", c, m.Str, want)
+		}
+	}
+}
+
+func TestStrictUTF8(t *testing.T) {
+	data := []byte("{\"str\":\"a\xffb\"}")
+	m := &example.Example{}
+	if err := fastjsonpb.Unmarshal(data, m); err != nil || m.Str != "a\xffb" {
+		t.Fatalf("lenient unmarshal: %q, %v", m.Str, err)
+	}
+	err := fastjsonpb.UnmarshalOptions{StrictUTF8: true}.Unmarshal(data, &example.Example{})
+	if !errors.Is(err, jsonparser.ErrInvalidUTF8) {
+		t.Errorf("strict unmarshal: %v", err)
+	}
+
+	b, err := fastjsonpb.Marshal(m)
+	if err != nil || !strings.Contains(string(b), `"a\ufffdb"`) {
+		t.Errorf("lenient marshal: %s, %v", b, err)
+	}
+	if _, err := (fastjsonpb.MarshalOptions{StrictUTF8: true}).Marshal(m); !errors.Is(err, buffer.ErrInvalidUTF8) {
+		t.Errorf("strict marshal: %v", err)
+	}
+	// 严格模式的设置不能影响后续使用Pool中Buffer的序列化
+	if _, err := fastjsonpb.Marshal(m); err != nil {
+		t.Errorf("marshal after strict: %v", err)
+	}
+}
+
+// 未开启preserve_unknown时生成代码调用Unknown
+func TestParserUnknown(t *testing.T) {
+	var got []string
+	p := jsonparser.New([]byte(`{"a": {"b":[1, 2]},"c":"d"}`))
+	p.SetOptions(jsonparser.Options{
+		OnUnknownField: func(path, key string, raw []byte) {
+			got = append(got, key+"|"+string(raw))
+		},
+	})
+	p.Symbol('{')
+	for !p.IsSymbol('}') {
+		key := p.Key()
+		p.AssertSymbol(':')
+		p.Unknown(key)
+		p.AssertSymbol(',')
+	}
+	p.Symbol('}')
+	p.End()
+	if want := `a|{"b":[1, 2]},c|"d"`; strings.Join(got, ",") != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// RFC 8785中的测试数据
+func TestCanonicalize(t *testing.T) {
+	numbers := map[uint64]string{
+		0x0000000000000000: "0",
+		0x8000000000000000: "0",
+		0x0000000000000001: "5e-324",
+		0x8000000000000001: "-5e-324",
+		0x7fefffffffffffff: "1.7976931348623157e+308",
+		0xffefffffffffffff: "-1.7976931348623157e+308",
+		0x4340000000000000: "9007199254740992",
+		0xc340000000000000: "-9007199254740992",
+		0x4430000000000000: "295147905179352830000",
+		0x44b52d02c7e14af5: "9.999999999999997e+22",
+		0x44b52d02c7e14af6: "1e+23",
+		0x44b52d02c7e14af7: "1.0000000000000001e+23",
+		0x444b1ae4d6e2ef4e: "999999999999999700000",
+		0x444b1ae4d6e2ef4f: "999999999999999900000",
+		0x444b1ae4d6e2ef50: "1e+21",
+		0x3eb0c6f7a0b5ed8c: "9.999999999999997e-7",
+		0x3eb0c6f7a0b5ed8d: "0.000001",
+		0x41b3de4355555553: "333333333.3333332",
+		0x41b3de4355555554: "333333333.33333325",
+		0x41b3de4355555555: "333333333.3333333",
+		0x41b3de4355555556: "333333333.3333334",
+		0x41b3de4355555557: "333333333.33333343",
+		0xbecbf647612f3696: "-0.0000033333333333333333",
+		0x43143ff3c1cb0959: "1424953923781206.2",
+	}
+	for bits, want := range numbers {
+		buf := buffer.New()
+		if err := buf.WriteCanonicalNumber(math.Float64frombits(bits)); err != nil || string(buf.Bytes()) != want {
+			t.Errorf("%#x: got %s, %v, want %s", bits, buf.Bytes(), err, want)
+		}
+		// 经过解析后结果不变
+		buf2 := buffer.New()
+		if err := jsonparser.Canonicalize(buf2, buf.Bytes()); err != nil || string(buf2.Bytes()) != want {
+			t.Errorf("Canonicalize(%s) = %s, %v", buf.Bytes(), buf2.Bytes(), err)
+		}
+	}
+	if err := buffer.New().WriteCanonicalNumber(math.NaN()); err != buffer.ErrCanonicalNumber {
+		t.Errorf("NaN: %v", err)
+	}
+
+	cases := map[string]string{
+		`{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		`{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter Dalet With Dagesh","1":"One","\ud83d\ude00":"Emoji: Grinning Face","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\",\"😀\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		`[{"b":{"d":[],"c":{}},"a":"<>&\u2028"}]`: "[{\"a\":\"<>&\u2028\",\"b\":{\"c\":{},\"d\":[]}}]",
+		// 能被float64精确表示的整数按ECMAScript的格式输出
+		`[1000000000000000000000,9007199254740992,-9007199254740992,-0]`: `[1e+21,9007199254740992,-9007199254740992,0]`,
+	}
+	for in, want := range cases {
+		buf := buffer.New()
+		if err := jsonparser.Canonicalize(buf, []byte(in)); err != nil || string(buf.Bytes()) != want {
+			t.Errorf("Canonicalize(%s)\n got %s, %v\nwant %s", in, buf.Bytes(), err, want)
+		}
+	}
+	for _, in := range []string{`{"a":1}x`, `[1,]`, `1e400`} {
+		if err := jsonparser.Canonicalize(buffer.New(), []byte(in)); err == nil {
+			t.Errorf("Canonicalize(%s) should fail", in)
+		}
+	}
+	// 不能精确表示的整数、重复的key返回错误，不能静默修改数据
+	for _, in := range []string{`{"a":9007199254740993}`, `[-9007199254740993]`, `1000000000000000000001`} {
+		if err := jsonparser.Canonicalize(buffer.New(), []byte(in)); !errors.Is(err, jsonparser.ErrCanonicalInteger) {
+			t.Errorf("Canonicalize(%s): %v", in, err)
+		}
+	}
+	if err := jsonparser.Canonicalize(buffer.New(), []byte(`{"a":1,"b":{"c":1,"c":2}}`)); !errors.Is(err, jsonparser.ErrDuplicateKey) {
+		t.Errorf("duplicate key: %v", err)
+	}
+}
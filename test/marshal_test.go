@@ -1,14 +1,28 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
 	json "encoding/json"
 
+	oldjsonpb "github.com/golang/protobuf/jsonpb"
 	jsoniter "github.com/json-iterator/go"
 	fastjsonpb "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/json"
 	"github.com/superjsf2010/protoc-gen-fastjsonpb/test/example"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/test/example/embed"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer"
 	jsonpb "google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/typepb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 var msg *example.Msg = &example.Msg{
@@ -73,3 +87,365 @@ func BenchmarkJsoniterMarshal(b *testing.B) {
 		jsoniter.Marshal(e)
 	}
 }
+
+func TestMarshalResultNotShared(t *testing.T) {
+	ret1, _ := fastjsonpb.Marshal(&example.Msg{Str: "a"})
+	ret2, _ := fastjsonpb.Marshal(&example.Msg{Str: "b"})
+	if string(ret1) != `{"str":"a"}` || string(ret2) != `{"str":"b"}` {
+		t.Errorf("unexpected marshal result: %s %s", ret1, ret2)
+	}
+}
+
+func TestBufferSizeClass(t *testing.T) {
+	buffer.SetMaxRetainSize(1 << 16)
+	defer buffer.SetMaxRetainSize(1 << 20)
+	big := buffer.New()
+	big.Write(make([]byte, 1<<17))
+	buffer.Put(big)
+	small := buffer.New()
+	if small == big || cap(small.Bytes()) >= 1<<17 {
+		t.Errorf("oversized buffer should not be pooled, cap=%d", cap(small.Bytes()))
+	}
+	b := buffer.NewSize(3000)
+	if cap(b.Bytes()) < 3000 {
+		t.Errorf("NewSize(3000) cap=%d", cap(b.Bytes()))
+	}
+	// 小于最小size class的Buffer不放回Pool
+	for i := 0; i < 10; i++ {
+		tiny := &buffer.Buffer{}
+		tiny.WriteByte('a')
+		buffer.Put(tiny)
+		if b := buffer.NewSize(64); cap(b.Bytes()) < 64 {
+			t.Fatalf("NewSize(64) cap=%d", cap(b.Bytes()))
+		}
+	}
+}
+
+func orderedMsg() *example.Ordered {
+	return &example.Ordered{
+		Choice:    &example.Ordered_OneofMsg{OneofMsg: &example.Msg{Str: "o"}},
+		Str:       "s",
+		In32Map:   map[int32]string{10: "a", -1: "b", 2: "c", -20: "d"},
+		In64Map:   map[int64]string{1 << 40: "a", -5: "b", 3: "c"},
+		Uin32Map:  map[uint32]string{4000000000: "a", 7: "b", 100: "c"},
+		Uin64Map:  map[uint64]*example.Msg{1 << 63: {In32: 1}, 9: {In32: 2}, 10: {}},
+		BolMap:    map[bool]string{true: "t", false: "f"},
+		StringMap: map[string]string{"b": "1", "a": "2", "ab": "3", "B": "4", "": "5"},
+	}
+}
+
+func TestMarshalDeterministic(t *testing.T) {
+	m := orderedMsg()
+	opts := fastjsonpb.MarshalOptions{Deterministic: true}
+	first, err := opts.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"str":"s","in32Map":{"-20":"d","-1":"b","2":"c","10":"a"},"in64Map":{"-5":"b","3":"c","1099511627776":"a"},` +
+		`"uin32Map":{"7":"b","100":"c","4000000000":"a"},"uin64Map":{"9":{"in32":2},"10":{},"9223372036854775808":{"in32":1}},` +
+		`"bolMap":{"false":"f","true":"t"},"stringMap":{"":"5","B":"4","a":"2","ab":"3","b":"1"},"oneofMsg":{"str":"o"}}`
+	if string(first) != want {
+		t.Errorf("got  %s\nwant %s", first, want)
+	}
+	for i := 0; i < 20; i++ {
+		if b, _ := opts.Marshal(m); string(b) != string(first) {
+			t.Fatalf("output changed: %s", b)
+		}
+	}
+
+	// 非string类型的key与protojson兼容
+	n := &example.Ordered{}
+	if err := jsonpb.Unmarshal(first, n); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(m, n) {
+		t.Errorf("protojson decoded %v", n)
+	}
+	std, _ := jsonpb.Marshal(m)
+	n = &example.Ordered{}
+	if err := fastjsonpb.Unmarshal(std, n); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(m, n) {
+		t.Errorf("decoded %v from %s", n, std)
+	}
+}
+
+func TestMarshalNumberOrder(t *testing.T) {
+	m := &example.Ordered{
+		Choice:  &example.Ordered_OneofStr{OneofStr: "o"},
+		Str:     "s",
+		In32Map: map[int32]string{1: "a"},
+		In64Map: map[int64]string{2: "b"},
+		BolMap:  map[bool]string{true: "t"},
+	}
+	b, err := fastjsonpb.MarshalOptions{NumberOrder: true}.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"bolMap":{"true":"t"},"in32Map":{"1":"a"},"str":"s","oneofStr":"o","in64Map":{"2":"b"}}`; string(b) != want {
+		t.Errorf("got  %s\nwant %s", b, want)
+	}
+	b, _ = fastjsonpb.Marshal(m)
+	if want := `{"str":"s","in32Map":{"1":"a"},"in64Map":{"2":"b"},"bolMap":{"true":"t"},"oneofStr":"o"}`; string(b) != want {
+		t.Errorf("got  %s\nwant %s", b, want)
+	}
+	// 字段号与声明顺序相同时不生成额外的方法
+	var e interface{} = &example.Example{}
+	if _, ok := e.(interface{ fastMarshalNumberOrder(*buffer.Buffer) }); ok {
+		t.Errorf("Example should not have fastMarshalNumberOrder")
+	}
+}
+
+func TestCanonicalDigest(t *testing.T) {
+	m := orderedMsg()
+	b, sum, err := fastjsonpb.CanonicalDigest(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"bolMap":{"false":"f","true":"t"},"in32Map":{"-1":"b","-20":"d","10":"a","2":"c"},"in64Map":{"-5":"b","1099511627776":"a","3":"c"},` +
+		`"oneofMsg":{"str":"o"},"str":"s","stringMap":{"":"5","B":"4","a":"2","ab":"3","b":"1"},"uin32Map":{"100":"c","4000000000":"a","7":"b"},` +
+		`"uin64Map":{"10":{},"9":{"in32":2},"9223372036854775808":{"in32":1}}}`
+	if string(b) != want {
+		t.Errorf("got  %s\nwant %s", b, want)
+	}
+	if sum != sha256.Sum256([]byte(want)) {
+		t.Errorf("digest mismatch")
+	}
+	// 与protojson一致，64位整数输出为字符串，超过2^53时不丢失精度
+	e := &example.Example{Flt64: 1e21, Flt32: 0.1, Str: "<\u2028>", In64: 1<<53 + 1}
+	b, err = fastjsonpb.MarshalOptions{Canonical: true}.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\"flt32\":0.1,\"flt64\":1e+21,\"in64\":\"9007199254740993\",\"str\":\"<\u2028>\"}"; string(b) != want {
+		t.Errorf("got  %s\nwant %s", b, want)
+	}
+}
+
+func TestMarshalEscape(t *testing.T) {
+	s := "<a href=\"x\">&amp;</a> é中😀\u2028\x01"
+	m := &example.Example{Str: s, StringMap: map[string]string{"<k>": "&"}}
+	def, err := fastjsonpb.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := fastjsonpb.MarshalOptions{EscapeHTML: true}.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	json.HTMLEscape(&want, def)
+	if string(b) != want.String() {
+		t.Errorf("EscapeHTML:\n got %s\nwant %s", b, want.String())
+	}
+
+	b, err = fastjsonpb.MarshalOptions{ASCIIOnly: true}.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range b {
+		if c >= 0x80 {
+			t.Fatalf("non-ASCII output %s", b)
+		}
+	}
+	if !strings.Contains(string(b), `\u00e9\u4e2d\ud83d\ude00\u2028\u0001`) {
+		t.Errorf("ASCIIOnly: %s", b)
+	}
+	n := &example.Example{}
+	if err := fastjsonpb.Unmarshal(b, n); err != nil || n.Str != s || n.StringMap["<k>"] != "&" {
+		t.Errorf("round trip %q, %v", n.Str, err)
+	}
+
+	// 选项不影响之后从Pool获取的Buffer
+	if b, _ := fastjsonpb.Marshal(m); string(b) != string(def) {
+		t.Errorf("default output changed: %s", b)
+	}
+}
+
+// 默认使用strconv的'f'格式，ProtojsonFloat时与protojson一致
+func TestMarshalProtojsonFloat(t *testing.T) {
+	for _, c := range []struct {
+		m         *example.Msg
+		def, want string
+	}{
+		{&example.Msg{Flt32: 1e-7, Flt64: 1e21}, `"flt32":0.0000001,"flt64":1000000000000000000000`, `"flt32":1e-7,"flt64":1e+21`},
+		{&example.Msg{Flt32: 1.5, Flt64: math.NaN()}, `"flt32":1.5,"flt64":NaN`, `"flt32":1.5,"flt64":"NaN"`},
+		{&example.Msg{Flt32: float32(math.Inf(-1)), Flt64: math.Inf(1)}, `"flt32":-Inf,"flt64":+Inf`, `"flt32":"-Infinity","flt64":"Infinity"`},
+	} {
+		def, _ := fastjsonpb.Marshal(c.m)
+		got, _ := fastjsonpb.MarshalOptions{ProtojsonFloat: true}.Marshal(c.m)
+		if !strings.Contains(string(def), c.def) || !strings.Contains(string(got), c.want) {
+			t.Errorf("got %s %s, want %s %s", def, got, c.def, c.want)
+		}
+	}
+}
+
+// 嵌入普通struct的message经过encoding/json、jsoniter时与FastMarshal输出一致
+func TestJSONMethods(t *testing.T) {
+	type wrapper struct {
+		Name  string        `json:"name"`
+		Item  *embed.Item   `json:"item"`
+		Items []*embed.Item `json:"items"`
+		Nil   *embed.Item   `json:"nil"`
+	}
+	item := &embed.Item{
+		Name:  "a<b",
+		Id:    1 << 60,
+		Kind:  embed.Kind_KIND_A,
+		Value: &embed.Item_Item{Item: &embed.Item{Value: &embed.Item_Str{Str: "s"}}},
+	}
+	w := wrapper{Name: "w", Item: item, Items: []*embed.Item{item, {}}}
+	ret, err := fastjsonpb.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"w","item":` + string(ret) + `,"items":[` + string(ret) + `,{}],"nil":null}`
+	std, err := json.Marshal(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// encoding/json会转义MarshalJSON输出中的<、>、&
+	var html bytes.Buffer
+	json.HTMLEscape(&html, []byte(want))
+	if string(std) != html.String() {
+		t.Fatalf("encoding/json: %s, want %s", std, html.String())
+	}
+	iter, err := jsoniter.Marshal(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(iter) != want {
+		t.Fatalf("jsoniter: %s, want %s", iter, want)
+	}
+
+	for name, unmarshal := range map[string]func([]byte, interface{}) error{
+		"encoding/json": json.Unmarshal,
+		"jsoniter":      jsoniter.Unmarshal,
+	} {
+		var got wrapper
+		if err := unmarshal(std, &got); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !proto.Equal(got.Item, item) || len(got.Items) != 2 || !proto.Equal(got.Items[0], item) || got.Nil != nil {
+			t.Fatalf("%s: %+v", name, got)
+		}
+	}
+}
+
+// 旧版jsonpb的选项映射到快速路径
+func TestJSONPBMethods(t *testing.T) {
+	item := &embed.Item{
+		Name:        "n",
+		Kind:        embed.Kind_KIND_A,
+		DisplayName: "d",
+		Items:       []*embed.Item{{Kind: embed.Kind_KIND_A}},
+		Value:       &embed.Item_Str{Str: "s"},
+	}
+	cases := []struct {
+		m    oldjsonpb.Marshaler
+		want string
+	}{
+		{oldjsonpb.Marshaler{}, `{"name":"n","id":"9007199254740993","kind":"KIND_A","items":[{"kind":"KIND_A"}],"displayName":"d","str":"s"}`},
+		{oldjsonpb.Marshaler{OrigName: true}, `{"name":"n","kind":"KIND_A","items":[{"kind":"KIND_A"}],"display_name":"d","str":"s"}`},
+		{oldjsonpb.Marshaler{EnumsAsInts: true}, `{"name":"n","kind":1,"items":[{"kind":1}],"displayName":"d","str":"s"}`},
+		{oldjsonpb.Marshaler{EmitDefaults: true}, `{"name":"n","id":"0","kind":"KIND_A","items":[{"name":"","id":"0","kind":"KIND_A","items":[],"displayName":"","child":null,"created":null,"tags":[],"timeouts":{},"syntax":"SYNTAX_PROTO2","syntaxes":[],"syntaxMap":{}}],"displayName":"d","child":null,"created":null,"tags":[],"timeouts":{},"syntax":"SYNTAX_PROTO2","syntaxes":[],"syntaxMap":{},"str":"s"}`},
+		{oldjsonpb.Marshaler{OrigName: true, EnumsAsInts: true, EmitDefaults: true, Indent: "  "}, `{"name":"n","id":"0","kind":1,"items":[{"name":"","id":"0","kind":1,"items":[],"display_name":"","child":null,"created":null,"tags":[],"timeouts":{},"syntax":0,"syntaxes":[],"syntax_map":{}}],"display_name":"d","child":null,"created":null,"tags":[],"timeouts":{},"syntax":0,"syntaxes":[],"syntax_map":{},"str":"s"}`},
+	}
+	for i, c := range cases {
+		// 与jsonpb一致，64位整数输出为字符串
+		item.Id = 0
+		if i == 0 {
+			item.Id = 1<<53 + 1
+		}
+		ret, err := c.m.MarshalToString(item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.m.Indent != "" {
+			if !strings.Contains(ret, "\n  \"name\": \"n\"") {
+				t.Fatalf("indent: %s", ret)
+			}
+			var compact bytes.Buffer
+			json.Compact(&compact, []byte(ret))
+			ret = compact.String()
+		}
+		if ret != c.want {
+			t.Fatalf("%+v: %s, want %s", c.m, ret, c.want)
+		}
+		// protojson可以解析，结果与原message相同
+		back := &embed.Item{}
+		if err := jsonpb.Unmarshal([]byte(ret), back); err != nil || !proto.Equal(back, item) {
+			t.Fatalf("%+v: %v %s", c.m, err, ret)
+		}
+	}
+}
+
+// 没有生成代码的message以及其他package中的字段使用protojson
+func TestProtojsonFallback(t *testing.T) {
+	ts := timestamppb.New(time.Unix(1, 0))
+	ret, err := fastjsonpb.Marshal(ts)
+	if err != nil || string(ret) != `"1970-01-01T00:00:01Z"` {
+		t.Fatalf("%v %s", err, ret)
+	}
+	gotTs := &timestamppb.Timestamp{}
+	if err := fastjsonpb.Unmarshal(ret, gotTs); err != nil || !proto.Equal(gotTs, ts) {
+		t.Fatalf("%v %v", err, gotTs)
+	}
+
+	// 去掉protojson输出的空白，string中的空白、转义保持不变
+	st, _ := structpb.NewStruct(map[string]interface{}{"a b": "c \\\" d", "l": []interface{}{1, " ", map[string]interface{}{}}})
+	ret, err = fastjsonpb.Marshal(st)
+	if err != nil || string(ret) != `{"a b":"c \\\" d","l":[1," ",{}]}` {
+		t.Fatalf("%v %s", err, ret)
+	}
+
+	item := &embed.Item{
+		Name:      "n",
+		Id:        1,
+		Created:   ts,
+		Tags:      []*wrapperspb.StringValue{wrapperspb.String("a"), wrapperspb.String("")},
+		Timeouts:  map[string]*durationpb.Duration{"read": durationpb.New(time.Second)},
+		Syntax:    typepb.Syntax_SYNTAX_PROTO3,
+		Syntaxes:  []typepb.Syntax{typepb.Syntax_SYNTAX_PROTO3, typepb.Syntax_SYNTAX_PROTO2},
+		SyntaxMap: map[string]typepb.Syntax{"k": typepb.Syntax_SYNTAX_PROTO3},
+		Value:     &embed.Item_Num{Num: wrapperspb.Int64(1 << 60)},
+	}
+	for _, opts := range []fastjsonpb.MarshalOptions{{}, {UseProtoNames: true, UseEnumNumbers: true, EmitUnpopulated: true}} {
+		opts.QuoteInt64 = true
+		ret, err := opts.Marshal(item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := jsonpb.MarshalOptions{UseProtoNames: opts.UseProtoNames, UseEnumNumbers: opts.UseEnumNumbers, EmitUnpopulated: opts.EmitUnpopulated}.Marshal(item)
+		if !jsonEqual(t, ret, want) {
+			t.Fatalf("%s, want %s", ret, want)
+		}
+		// EmitUnpopulated输出的null需要NullAsUnset
+		got := &embed.Item{}
+		if err := (fastjsonpb.UnmarshalOptions{NullAsUnset: true}).Unmarshal(ret, got); err != nil || !proto.Equal(got, item) {
+			t.Fatalf("%v %v", err, got)
+		}
+	}
+
+	// 合并模式、null、未知字段与生成代码的处理一致
+	got := proto.Clone(item).(*embed.Item)
+	in := `{"created":{"seconds":2},"tags":["b"],"timeouts":{"write":"2s"},"syntax":null,"syntaxes":["SYNTAX_PROTO2"]}`
+	if err := (fastjsonpb.UnmarshalOptions{Merge: true, NullAsUnset: true}).Unmarshal([]byte(in), got); err == nil {
+		t.Fatal("Timestamp must be a string")
+	}
+	in = `{"created":"1970-01-01T00:00:02Z","tags":["b"],"timeouts":{"write":"2s"},"syntax":null,"syntaxes":["SYNTAX_PROTO2"]}`
+	if err := (fastjsonpb.UnmarshalOptions{Merge: true, NullAsUnset: true}).Unmarshal([]byte(in), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Created.GetSeconds() != 2 || len(got.Tags) != 3 || len(got.Timeouts) != 2 || got.Syntax != typepb.Syntax_SYNTAX_PROTO3 || len(got.Syntaxes) != 3 {
+		t.Fatalf("merge: %v", got)
+	}
+	if err := fastjsonpb.Unmarshal([]byte(`{"syntax":"SYNTAX_PROTO4"}`), got); err == nil {
+		t.Fatal("unknown enum name accepted")
+	}
+	if err := fastjsonpb.Unmarshal([]byte(`{"num":{"value":1}}`), got); err == nil {
+		t.Fatal("Int64Value must be a number or string")
+	}
+}
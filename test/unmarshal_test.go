@@ -1,14 +1,26 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 
 	json "encoding/json"
 
+	oldjsonpb "github.com/golang/protobuf/jsonpb"
 	jsoniter "github.com/json-iterator/go"
 	fastjsonpb "github.com/superjsf2010/protoc-gen-fastjsonpb/encoding/json"
 	"github.com/superjsf2010/protoc-gen-fastjsonpb/test/example"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/test/example/embed"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/arena"
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/jsonparser"
 	jsonpb "google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 var byts []byte = []byte(`{"str":"string","in32":32,"in64":64,"uin32":32,"uin64":64,"flt32":32.123,"flt64":64.123,"byts":"Ynl0ZXM=","typ":"TYPB","msg":{"str":"string"},"strArr":["string"],"typArr":["TYPA","TYPB"],"stringMap":{"key1":"val1","key2":"val2"},"nestedTyp":"TYPB","nestedMsg":{"str":"string"},"oneofBol":false,"unknown":{"str":"string","in32":32,"in64":64,"uin32":32,"uin64":64,"flt32":32.123,"flt64":64.123,"byts":"Ynl0ZXM="}}`)
@@ -23,6 +35,19 @@ func BenchmarkFastJsonpbUnmarshal(b *testing.B) {
 	}
 }
 
+func BenchmarkFastJsonpbUnmarshalArena(b *testing.B) {
+	a := arena.New()
+	opts := fastjsonpb.UnmarshalOptions{Arena: a}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e := example.ExampleArenaNew(a)
+		opts.Unmarshal(byts, e)
+		if i%100 == 99 {
+			a.Free()
+		}
+	}
+}
+
 func BenchmarkStdJsonpbUnmarshal(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -46,3 +71,587 @@ func BenchmarkJsoniterUnmarshal(b *testing.B) {
 		jsoniter.Unmarshal(byts, e)
 	}
 }
+
+var msgByts []byte = []byte(`{"bol":true,"str":"string","in32":32,"in64":64,"uin32":32,"uin64":64,"flt32":32.123,"flt64":64.123}`)
+
+func TestUnmarshalAllocs(t *testing.T) {
+	m := example.MsgNew()
+	opts := fastjsonpb.UnmarshalOptions{ZeroCopy: true}
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := opts.Unmarshal(msgByts, m); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("ZeroCopy Unmarshal allocs = %v, want 0", allocs)
+	}
+	// 默认模式下所有string共用一次分配
+	allocs = testing.AllocsPerRun(100, func() {
+		if err := fastjsonpb.Unmarshal(msgByts, m); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 1 {
+		t.Errorf("Unmarshal allocs = %v, want 1", allocs)
+	}
+}
+
+func TestUnmarshalCopy(t *testing.T) {
+	data := []byte(`{"str":"string","byts":"Ynl0ZXM=","strArr":["a\"b","c"]}`)
+	m := &example.Example{}
+	if err := fastjsonpb.Unmarshal(data, m); err != nil {
+		t.Fatal(err)
+	}
+	// 复用输入数据不影响解析结果
+	for i := range data {
+		data[i] = 'x'
+	}
+	if m.Str != "string" || string(m.Byts) != "bytes" || m.StrArr[0] != `a"b` || m.StrArr[1] != "c" {
+		t.Errorf("decoded message changed with input: %v", m)
+	}
+}
+
+func TestUnmarshalAfterError(t *testing.T) {
+	m := &example.Msg{}
+	if err := fastjsonpb.Unmarshal([]byte(`{"str":"string","in32":}`), m); err == nil {
+		t.Fatal("expected syntax error")
+	}
+	m = &example.Msg{}
+	if err := fastjsonpb.Unmarshal(msgByts, m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Str != "string" || m.In64 != 64 {
+		t.Errorf("unexpected result after error: %v", m)
+	}
+}
+
+func TestUnmarshalArena(t *testing.T) {
+	data := []byte(`{"str":"string","msg":{"str":"m"},"in32Arr":[1,2,3,4,5,6,7,8,9,10],"strArr":["a","b"],"msgArr":[{"in32":1},{"in32":2}],"msgMap":{"k":{"str":"v"}}}`)
+	a := arena.New()
+	m := example.ExampleArenaNew(a)
+	if err := (fastjsonpb.UnmarshalOptions{Arena: a}).Unmarshal(data, m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Str != "string" || m.Msg.Str != "m" || len(m.In32Arr) != 10 || m.In32Arr[9] != 10 ||
+		len(m.StrArr) != 2 || len(m.MsgArr) != 2 || m.MsgArr[1].In32 != 2 || m.MsgMap["k"].Str != "v" {
+		t.Errorf("unexpected result: %v", m)
+	}
+	a.Free()
+}
+
+func TestDestructorReuse(t *testing.T) {
+	data := []byte(`{"in32Arr":[1,2,3],"msgArr":[{"in32":1},{"in32":2}],"stringMap":{"k":"v"}}`)
+	m := example.ExampleNew()
+	if err := fastjsonpb.Unmarshal(data, m); err != nil {
+		t.Fatal(err)
+	}
+	in32Arr, msgArr, stringMap := m.In32Arr, m.MsgArr, reflect.ValueOf(m.StringMap).Pointer()
+	m.Destructor()
+	if len(m.In32Arr) != 0 || cap(m.In32Arr) != cap(in32Arr) || len(m.MsgArr) != 0 || len(m.StringMap) != 0 || m.StringMap == nil {
+		t.Fatalf("Destructor should keep capacity: %v", m)
+	}
+	// 数组中的message已经放回Pool，底层数组中不再引用
+	if msgArr[0] != nil || msgArr[1] != nil {
+		t.Fatalf("Destructor should release nested messages: %v", msgArr)
+	}
+	if example.ExampleNew() != m {
+		t.Skip("message not returned by pool")
+	}
+	// 反序列化前的FastReset保留Destructor留下的空间，之后在原有的底层数组中追加
+	if err := fastjsonpb.Unmarshal([]byte(`{"in32Arr":[4],"msgArr":[{"str":"s"}],"stringMap":{"a":"b"}}`), m); err != nil {
+		t.Fatal(err)
+	}
+	if &m.In32Arr[0] != &in32Arr[0] || &m.MsgArr[0] != &msgArr[0] || reflect.ValueOf(m.StringMap).Pointer() != stringMap {
+		t.Errorf("Unmarshal should reuse capacity: %v", m)
+	}
+	ret, _ := fastjsonpb.Marshal(m)
+	if string(ret) != `{"in32Arr":[4],"msgArr":[{"str":"s"}],"stringMap":{"a":"b"}}` {
+		t.Errorf("unexpected marshal result: %s", ret)
+	}
+}
+
+func TestUnmarshalKeepsCallerData(t *testing.T) {
+	nested := &example.Msg{Str: "keep"}
+	shared := []*example.Msg{nested, {In32: 1}}
+	strs := []string{"a", "b"}
+	m := &example.Example{Msg: nested, MsgArr: shared, StrArr: strs, MsgMap: map[string]*example.Msg{"k": nested}}
+	if err := fastjsonpb.Unmarshal([]byte(`{"msg":{"in32":1},"msgArr":[{"str":"x"}],"strArr":["c"],"msgMap":{"j":{}}}`), m); err != nil {
+		t.Fatal(err)
+	}
+	// 调用方持有的message、数组不能被放回Pool或覆盖
+	if nested.Str != "keep" || shared[0] != nested || shared[1].In32 != 1 || strs[0] != "a" {
+		t.Errorf("caller data modified: %v %v %v", nested, shared, strs)
+	}
+	if m.Msg.In32 != 1 || m.MsgArr[0].Str != "x" || m.StrArr[0] != "c" || len(m.MsgMap) != 1 {
+		t.Errorf("unexpected result: %v", m)
+	}
+
+	// 直接调用FastUnmarshal时同样不修改调用方的数组、map
+	m = &example.Example{MsgArr: shared, StrArr: strs, MsgMap: map[string]*example.Msg{"k": nested}}
+	mm := m.MsgMap
+	m.FastUnmarshal(jsonparser.New([]byte(`{"msgArr":[{"str":"y"}],"strArr":["d"],"msgMap":{"j":{}}}`)))
+	if shared[0] != nested || nested.Str != "keep" || strs[0] != "a" || mm["k"] != nested || len(mm) != 1 {
+		t.Errorf("caller data modified: %v %v %v", shared, strs, mm)
+	}
+
+	// 长度之外的元素可能仍属于调用方，不会被放回Pool
+	m = &example.Example{MsgArr: shared[:0]}
+	m.FastUnmarshal(jsonparser.New([]byte(`{"msgArr":[]}`)))
+	if shared[0] != nested || nested.Str != "keep" || shared[1].In32 != 1 {
+		t.Errorf("caller data modified: %v", shared)
+	}
+}
+
+func TestUnmarshalLimits(t *testing.T) {
+	deep := `{"unknown":` + strings.Repeat("[", 1000000) + strings.Repeat("]", 1000000) + `}`
+	cases := []struct {
+		opts fastjsonpb.UnmarshalOptions
+		data string
+		err  error
+	}{
+		{fastjsonpb.UnmarshalOptions{DiscardUnknown: true}, deep, jsonparser.ErrMaxDepth},
+		{fastjsonpb.UnmarshalOptions{MaxDepth: 2}, `{"msg":{"str":"a"}}`, nil},
+		{fastjsonpb.UnmarshalOptions{MaxDepth: 2}, `{"msgArr":[{"str":"a"}]}`, jsonparser.ErrMaxDepth},
+		{fastjsonpb.UnmarshalOptions{MaxSize: 10}, `{"str":"string"}`, jsonparser.ErrMaxSize},
+		{fastjsonpb.UnmarshalOptions{MaxStringLen: 3}, `{"str":"abc"}`, nil},
+		{fastjsonpb.UnmarshalOptions{MaxStringLen: 3}, `{"str":"abcd"}`, jsonparser.ErrMaxStringLen},
+		{fastjsonpb.UnmarshalOptions{MaxElements: 3}, `{"in32Arr":[1,2,3]}`, nil},
+		{fastjsonpb.UnmarshalOptions{MaxElements: 3}, `{"in32Arr":[1,2,3,4]}`, jsonparser.ErrMaxElements},
+		{fastjsonpb.UnmarshalOptions{MaxElements: 3}, `{"stringMap":{"a":"","b":"","c":"","d":""}}`, jsonparser.ErrMaxElements},
+		{fastjsonpb.UnmarshalOptions{MaxElements: 3, DiscardUnknown: true}, `{"unknown":[1,2,3,4]}`, jsonparser.ErrMaxElements},
+	}
+	for _, c := range cases {
+		err := c.opts.Unmarshal([]byte(c.data), &example.Example{})
+		if !errors.Is(err, c.err) {
+			name := c.data
+			if len(name) > 40 {
+				name = name[:40]
+			}
+			t.Errorf("Unmarshal(%s) err = %v, want %v", name, err, c.err)
+		}
+	}
+}
+
+func TestUnmarshalDuplicate(t *testing.T) {
+	data := []byte(`{"str":"a","stringMap":{"k":"1","k":"2"},"str":"b"}`)
+	m := &example.Example{}
+	if err := fastjsonpb.Unmarshal(data, m); err != nil || m.Str != "b" || m.StringMap["k"] != "2" {
+		t.Errorf("last wins: %v %v", m, err)
+	}
+	m = &example.Example{}
+	opts := fastjsonpb.UnmarshalOptions{Duplicate: jsonparser.DuplicateFirstWins}
+	if err := opts.Unmarshal(data, m); err != nil || m.Str != "a" || m.StringMap["k"] != "1" {
+		t.Errorf("first wins: %v %v", m, err)
+	}
+	opts = fastjsonpb.UnmarshalOptions{Duplicate: jsonparser.DuplicateError}
+	if err := opts.Unmarshal(data, &example.Example{}); !errors.Is(err, jsonparser.ErrDuplicateKey) {
+		t.Errorf("duplicate field err = %v", err)
+	}
+	oneof := []byte(`{"oneofStr":"a","oneofIn32":1}`)
+	if err := opts.Unmarshal(oneof, &example.Example{}); !errors.Is(err, jsonparser.ErrDuplicateKey) {
+		t.Errorf("multiple oneof members err = %v", err)
+	}
+}
+
+// 合并模式下message中原有的key不算重复，只检查同一个对象中的key，生成代码和反射一致
+func TestUnmarshalMergeDuplicate(t *testing.T) {
+	old := &example.Example{StringMap: map[string]string{"k": "old", "o": "o"}, In32Map: map[string]int32{"a": 1}}
+	want := &example.Example{StringMap: map[string]string{"k": "new", "o": "o"}, In32Map: map[string]int32{"a": 2}}
+	mapValue := func(m proto.Message) string {
+		mr := m.ProtoReflect()
+		fd := mr.Descriptor().Fields().ByName("string_map")
+		return mr.Get(fd).Map().Get(protoreflect.ValueOfString("k").MapKey()).String()
+	}
+	for _, policy := range []jsonparser.DuplicatePolicy{jsonparser.DuplicateError, jsonparser.DuplicateFirstWins} {
+		opts := fastjsonpb.UnmarshalOptions{Merge: true, Duplicate: policy}
+		for _, c := range []struct{ m, want proto.Message }{
+			{proto.Clone(old), want},
+			{toDynamic(t, old), toDynamic(t, want)},
+		} {
+			if err := opts.Unmarshal([]byte(`{"stringMap":{"k":"new"},"in32Map":{"a":2}}`), c.m); err != nil || !proto.Equal(c.m, c.want) {
+				t.Fatalf("%v %T: %v %v", policy, c.m, err, c.m)
+			}
+			// 同一个对象中重复的key仍然按照选项处理
+			err := opts.Unmarshal([]byte(`{"stringMap":{"k":"a","k":"b"}}`), c.m)
+			if policy == jsonparser.DuplicateError && !errors.Is(err, jsonparser.ErrDuplicateKey) {
+				t.Errorf("%T: duplicate key err = %v", c.m, err)
+			}
+			if policy == jsonparser.DuplicateFirstWins && (err != nil || mapValue(c.m) != "a") {
+				t.Errorf("%T: first wins %v %v", c.m, err, c.m)
+			}
+		}
+	}
+}
+
+func TestUnmarshalBytes(t *testing.T) {
+	want := []byte{0xfb, 0xff, 0xfe, 'a'}
+	for _, v := range []string{`"+//+YQ=="`, `"+//+YQ"`, `"-__-YQ=="`, `"-__-YQ"`, `"+\/\/+YQ=="`} {
+		m := &example.Example{}
+		if err := fastjsonpb.Unmarshal([]byte(`{"byts":`+v+`}`), m); err != nil {
+			t.Fatalf("%s: %v", v, err)
+		}
+		if string(m.Byts) != string(want) {
+			t.Errorf("%s: got %v, want %v", v, m.Byts, want)
+		}
+	}
+	for _, v := range []string{`"+//+Y"`, `"+/-_"`, `"YQ=a"`, `"Y Q=="`} {
+		if err := fastjsonpb.Unmarshal([]byte(`{"byts":`+v+`}`), &example.Example{}); !errors.Is(err, jsonparser.ErrBase64) {
+			t.Errorf("%s: err = %v", v, err)
+		}
+	}
+
+	m := &example.Example{Byts: want, BytsArr: [][]byte{{}, []byte("bytes")}}
+	b, err := fastjsonpb.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &example.Example{}
+	if err := fastjsonpb.Unmarshal(b, n); err != nil {
+		t.Fatal(err)
+	}
+	if string(n.Byts) != string(want) || len(n.BytsArr) != 2 || string(n.BytsArr[1]) != "bytes" {
+		t.Errorf("round trip %s: %v", b, n)
+	}
+}
+
+// 数字字段的解析规则与protojson对比
+func TestUnmarshalNumbers(t *testing.T) {
+	values := []string{
+		`0`, `-0`, `1`, `-1`, `1e3`, `1E+3`, `1.5e1`, `1.50e1`, `100.000`, `0.05e2`, `1000e-3`, `1e-1`, `1.5`, `-0.0`,
+		`2147483647`, `2147483648`, `-2147483648`, `-2147483649`, `4294967295`, `4294967296`,
+		`9223372036854775807`, `9223372036854775808`, `-9223372036854775808`, `-9223372036854775809`,
+		`18446744073709551615`, `18446744073709551616`, `1e19`, `1e20`, `1e400`, `1e-400`, `123456789e-9`, `1.8446744073709551615e19`,
+		`"42"`, `"-42"`, `"1e3"`, `"1.5"`, `" 42"`, `"42 "`, `"0x10"`, `"+1"`, `""`, `"abc"`, `"42"`,
+		`"NaN"`, `"Infinity"`, `"-Infinity"`, `3.4e38`, `3.5e38`, `1.7e308`, `1.8e308`, `1e-50`, `"1.5e300"`,
+		`true`, `[]`,
+	}
+	fields := []string{"in32", "in64", "uin32", "uin64", "flt32", "flt64"}
+	for _, f := range fields {
+		for _, v := range values {
+			data := []byte(`{"` + f + `":` + v + `}`)
+			want := &example.Example{}
+			wantErr := jsonpb.Unmarshal(data, want)
+			got := &example.Example{}
+			err := fastjsonpb.Unmarshal(data, got)
+			if (err != nil) != (wantErr != nil) {
+				t.Errorf("%s: err = %v, protojson err = %v", data, err, wantErr)
+				continue
+			}
+			if err == nil && got.String() != want.String() {
+				t.Errorf("%s: got %v, protojson %v", data, got, want)
+			}
+		}
+	}
+
+	errs := map[string]error{
+		`{"in32":2147483648}`:             jsonparser.ErrOverflow,
+		`{"uin32":-1}`:                    jsonparser.ErrOverflow,
+		`{"uin64":1e20}`:                  jsonparser.ErrOverflow,
+		`{"in64":"-9223372036854775809"}`: jsonparser.ErrOverflow,
+		`{"flt32":3.5e38}`:                jsonparser.ErrOverflow,
+		`{"in32":1.5}`:                    jsonparser.ErrNotInteger,
+		`{"in64":"1e-1"}`:                 jsonparser.ErrNotInteger,
+		`{"in32":"1 "}`:                   jsonparser.ErrNumber,
+		`{"flt64":"inf"}`:                 jsonparser.ErrNumber,
+	}
+	for data, want := range errs {
+		if err := fastjsonpb.Unmarshal([]byte(data), &example.Example{}); !errors.Is(err, want) {
+			t.Errorf("%s: err = %v, want %v", data, err, want)
+		}
+	}
+}
+
+func TestUnmarshalUnknown(t *testing.T) {
+	cases := []struct {
+		data string
+		path string
+	}{
+		{`{"str":"a","strr":"b"}`, `"strr"`},
+		{`{"msg":{"in32":1,"typo":{}}}`, `"msg.typo"`},
+		{`{"msgArr":[{},{"str":"a","x\"y":1}]}`, `"msgArr[1].x\"y"`},
+		{`{"str":"a","nestedMsg":{"str":"b"},"msgArr":[{"str":"c","typo":1}]}`, `"msgArr[0].typo"`},
+	}
+	for _, c := range cases {
+		// 与protojson一致，零值的UnmarshalOptions、Unmarshal遇到未知字段返回错误
+		err := fastjsonpb.UnmarshalOptions{}.Unmarshal([]byte(c.data), &example.Example{})
+		if !errors.Is(err, jsonparser.ErrUnknownField) || !strings.Contains(err.Error(), c.path) {
+			t.Errorf("%s: err = %v, want path %s", c.data, err, c.path)
+		}
+		if err := fastjsonpb.Unmarshal([]byte(c.data), &example.Example{}); !errors.Is(err, jsonparser.ErrUnknownField) {
+			t.Errorf("%s: Unmarshal err = %v", c.data, err)
+		}
+		if err := (fastjsonpb.UnmarshalOptions{DiscardUnknown: true}).Unmarshal([]byte(c.data), &example.Example{}); err != nil {
+			t.Errorf("%s: DiscardUnknown err = %v", c.data, err)
+		}
+	}
+}
+
+// 示例代码使用preserve_unknown=true生成
+func TestUnmarshalPreserveUnknown(t *testing.T) {
+	data := []byte(`{"str":"a","future":{"x":[1, 2]},"n":1e3,"msg":{"in32":1,"extra":"é"},"k\"ey":null}`)
+	opts := fastjsonpb.UnmarshalOptions{DiscardUnknown: true}
+	m := example.ExampleNew()
+	if err := opts.Unmarshal(data, m); err != nil {
+		t.Fatal(err)
+	}
+	m.Str = "b"
+	b, err := fastjsonpb.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"str":"b"`, `"future":{"x":[1,2]}`, `"n":1e3`, `"extra":"é"`, `"k\"ey":null`} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("%s does not contain %s", b, want)
+		}
+	}
+	if !json.Valid(b) {
+		t.Errorf("invalid json %s", b)
+	}
+
+	// 未知字段不写入protobuf二进制格式，已有的二进制未知字段保持不变
+	bin := protowire.AppendTag(nil, 100, protowire.VarintType)
+	bin = protowire.AppendVarint(bin, 1)
+	n := &example.Example{}
+	n.ProtoReflect().SetUnknown(bin)
+	if err := opts.Unmarshal(data, n); err != nil {
+		t.Fatal(err)
+	}
+	if b2, _ := fastjsonpb.Marshal(n); !strings.Contains(string(b2), `"future":{"x":[1,2]}`) {
+		t.Errorf("unknown fields not preserved: %s", b2)
+	}
+	pb, err := proto.Marshal(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := proto.Marshal(&example.Example{Str: "a", Msg: &example.Msg{In32: 1}})
+	if string(pb) != string(want)+string(bin) {
+		t.Errorf("proto.Marshal = %x, want %x", pb, string(want)+string(bin))
+	}
+	n.FastReset()
+	if b2, _ := fastjsonpb.Marshal(n); string(b2) != `{}` || string(n.ProtoReflect().GetUnknown()) != string(bin) {
+		t.Errorf("FastReset: %s %x", b2, n.ProtoReflect().GetUnknown())
+	}
+
+	m.Destructor()
+	m = example.ExampleNew()
+	if b, _ := fastjsonpb.Marshal(m); string(b) != `{}` {
+		t.Errorf("unknown fields not reset: %s", b)
+	}
+}
+
+// 保存的未知字段按序列化选项重新转义、检查UTF-8，Merge时同一个key只保留最后一个value
+func TestUnmarshalPreserveUnknownOutput(t *testing.T) {
+	m := &example.Example{}
+	opts := fastjsonpb.UnmarshalOptions{DiscardUnknown: true}
+	if err := opts.Unmarshal([]byte(`{"u":"<é>","v":{"k<":["\u00e9"]},"w":1}`), m); err != nil {
+		t.Fatal(err)
+	}
+	opts.Merge = true
+	if err := opts.Unmarshal([]byte(`{"w":2,"x":3}`), m); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		opts fastjsonpb.MarshalOptions
+		want string
+	}{
+		{want: `{"u":"<é>","v":{"k<":["é"]},"w":2,"x":3}`},
+		{opts: fastjsonpb.MarshalOptions{EscapeHTML: true}, want: `{"u":"\u003cé\u003e","v":{"k\u003c":["é"]},"w":2,"x":3}`},
+		{opts: fastjsonpb.MarshalOptions{ASCIIOnly: true}, want: `{"u":"<\u00e9>","v":{"k<":["\u00e9"]},"w":2,"x":3}`},
+	}
+	for _, c := range cases {
+		if b, err := c.opts.Marshal(m); err != nil || string(b) != c.want {
+			t.Errorf("%+v: %s %v, want %s", c.opts, b, err, c.want)
+		}
+	}
+
+	n := &example.Example{}
+	if err := opts.Unmarshal([]byte("{\"u\":[\"\xff\"]}"), n); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := (fastjsonpb.MarshalOptions{StrictUTF8: true}).Marshal(n); !errors.Is(err, jsonparser.ErrInvalidUTF8) {
+		t.Errorf("%s %v", b, err)
+	}
+	if b, err := fastjsonpb.Marshal(n); err != nil || string(b) != `{"u":["\ufffd"]}` {
+		t.Errorf("%s %v", b, err)
+	}
+}
+
+func TestUnmarshalOnUnknownField(t *testing.T) {
+	data := []byte(`{"str":"a","old":1,"msg":{"in32":1,"typo":"x"},"msgArr":[{},{"deprecated":[true]}]}`)
+	var got []string
+	opts := fastjsonpb.UnmarshalOptions{
+		DiscardUnknown: true,
+		OnUnknownField: func(path, key string, raw []byte) {
+			got = append(got, path+"|"+key+"|"+string(raw))
+		},
+	}
+	m := &example.Example{}
+	if err := opts.Unmarshal(data, m); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`|old|1`, `msg|typo|"x"`, `msgArr[1]|deprecated|[true]`}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if m.Str != "a" || m.Msg.In32 != 1 {
+		t.Errorf("unexpected result: %v", m)
+	}
+
+	got = nil
+	opts.DiscardUnknown = false
+	if err := opts.Unmarshal(data, &example.Example{}); !errors.Is(err, jsonparser.ErrUnknownField) || len(got) != 0 {
+		t.Errorf("err = %v, callback = %q", err, got)
+	}
+}
+
+// 默认先清空message，Merge时与proto.Merge的结果一致
+// 默认字段的值不能为null，NullAsUnset时与protojson一致作为未设置处理
+func TestUnmarshalNull(t *testing.T) {
+	in := []byte(`{"str":null,"msg":null,"strArr":null,"stringMap":null,"oneofStr":null,"in32":1}`)
+	if err := fastjsonpb.Unmarshal(in, &example.Example{}); err == nil {
+		t.Fatal("null accepted by default")
+	}
+	got := &example.Example{Str: "a", Msg: &example.Msg{}}
+	if err := (fastjsonpb.UnmarshalOptions{NullAsUnset: true}).Unmarshal(in, got); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, &example.Example{In32: 1}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestUnmarshalMerge(t *testing.T) {
+	old := func() *example.Example {
+		return &example.Example{
+			Str:          "a",
+			In64:         5,
+			In32Arr:      []int32{1},
+			MsgArr:       []*example.Msg{{Str: "m1"}},
+			StringMap:    map[string]string{"k1": "v1", "k2": "v2"},
+			Msg:          &example.Msg{In32: 1, Str: "x"},
+			TestOneof:    &example.Example_OneofMsg{OneofMsg: &example.Msg{In32: 2}},
+			NestedMsg:    &example.Example_NestedMsg{Str: "n"},
+			BytsArr:      [][]byte{[]byte("b")},
+			NestedTyp:    example.Example_TYPB,
+			Uin32Map:     map[string]uint32{"u": 1},
+			Flt64Arr:     []float64{1.5},
+			Bol:          true,
+			Byts:         []byte("old"),
+			StrArr:       []string{"s"},
+			NestedMsgMap: map[string]*example.Example_NestedMsg{"k": {Str: "v"}},
+		}
+	}
+	data := []byte(`{"str":"b","in32Arr":[2,3],"msgArr":[{"str":"m2"}],"stringMap":{"k2":"v3","k3":"v4"},"msg":{"str":"y"},"oneofMsg":{"str":"o"},"byts":"bmV3","nestedMsgMap":{"k":{}}}`)
+	src := &example.Example{}
+	if err := jsonpb.Unmarshal(data, src); err != nil {
+		t.Fatal(err)
+	}
+
+	m := old()
+	if err := fastjsonpb.Unmarshal(data, m); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(m, src) {
+		t.Errorf("replace:\n got %v\nwant %v", m, src)
+	}
+
+	m = old()
+	if err := (fastjsonpb.UnmarshalOptions{Merge: true}).Unmarshal(data, m); err != nil {
+		t.Fatal(err)
+	}
+	want := old()
+	proto.Merge(want, src)
+	if !proto.Equal(m, want) {
+		t.Errorf("merge:\n got %v\nwant %v", m, want)
+	}
+}
+
+// 旧版jsonpb调用UnmarshalJSONPB：接受json名字和proto名字，合并到已有数据，顶层null不做处理
+func TestUnmarshalJSONPB(t *testing.T) {
+	item := &embed.Item{Name: "old", Items: []*embed.Item{{Name: "a"}}}
+	u := oldjsonpb.Unmarshaler{}
+	in := `{"display_name":"d","kind":1,"items":[{"name":"b"}],"child":{"displayName":"c"}}`
+	if err := u.Unmarshal(bytes.NewReader([]byte(in)), item); err != nil {
+		t.Fatal(err)
+	}
+	want := &embed.Item{
+		Name:        "old",
+		Kind:        embed.Kind_KIND_A,
+		DisplayName: "d",
+		Items:       []*embed.Item{{Name: "a"}, {Name: "b"}},
+		Child:       &embed.Item{DisplayName: "c"},
+	}
+	if !proto.Equal(item, want) {
+		t.Fatalf("got %v, want %v", item, want)
+	}
+	if err := u.Unmarshal(bytes.NewReader([]byte(`null`)), item); err != nil || !proto.Equal(item, want) {
+		t.Fatalf("null: %v %v", err, item)
+	}
+	// 同一字段的两种名字视为同一个字段，默认后出现的生效
+	dup := &embed.Item{}
+	if err := u.Unmarshal(bytes.NewReader([]byte(`{"displayName":"a","display_name":"b"}`)), dup); err != nil || dup.DisplayName != "b" {
+		t.Fatalf("duplicate: %v %v", err, dup)
+	}
+	if err := u.Unmarshal(bytes.NewReader([]byte(`{"unknown":1}`)), &embed.Item{}); !errors.Is(err, jsonparser.ErrUnknownField) {
+		t.Fatalf("unknown field: %v", err)
+	}
+	u.AllowUnknownFields = true
+	if err := u.Unmarshal(bytes.NewReader([]byte(`{"unknown":1}`)), &embed.Item{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnmarshalByName(t *testing.T) {
+	opts := fastjsonpb.UnmarshalOptions{DiscardUnknown: true}
+	m, release, err := opts.UnmarshalByName("example.Example", byts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &example.Example{}
+	if err := opts.Unmarshal(byts, want); err != nil {
+		t.Fatal(err)
+	}
+	e, ok := m.(*example.Example)
+	if !ok || !proto.Equal(e, want) {
+		t.Fatalf("got %T %v", m, m)
+	}
+	// 放回Pool后再次获取的message已经清空，重复调用release不会再次放回Pool
+	release()
+	release()
+	n, releaseN, err := fastjsonpb.NewByName("example.Example")
+	if err != nil || !proto.Equal(n, &example.Example{}) {
+		t.Fatalf("NewByName: %v %v", err, n)
+	}
+	if o, _, _ := fastjsonpb.NewByName("example.Example"); o == n {
+		t.Fatal("message released twice")
+	}
+	releaseN()
+
+	// Any的type URL、嵌套message、其他文件中的message
+	m, _, err = fastjsonpb.UnmarshalByName("type.googleapis.com/example.Example.NestedMsg", []byte(`{"str":"n"}`))
+	if err != nil || m.(*example.Example_NestedMsg).Str != "n" {
+		t.Fatalf("nested: %v %v", err, m)
+	}
+	m, _, err = opts.UnmarshalByName("embed.Item", []byte(`{"name":"i","unknown":1}`))
+	if err != nil || m.(*embed.Item).Name != "i" {
+		t.Fatalf("embed: %v %v", err, m)
+	}
+	// 没有生成代码的类型从protoregistry查找，release不做处理
+	m, release, err = fastjsonpb.UnmarshalByName("google.protobuf.Int64Value", []byte(`"5"`))
+	if err != nil || m.(*wrapperspb.Int64Value).Value != 5 {
+		t.Fatalf("wkt: %v %v", err, m)
+	}
+	release()
+
+	if _, _, err := fastjsonpb.UnmarshalByName("example.Missing", byts); err == nil || !strings.Contains(err.Error(), "example.Missing") {
+		t.Fatalf("missing: %v", err)
+	}
+	if _, _, err := fastjsonpb.UnmarshalByName("example.Example", byts); !errors.Is(err, jsonparser.ErrUnknownField) {
+		t.Fatalf("unknown field: %v", err)
+	}
+	// 重复注册输出警告，保留第一次注册的类型
+	fastjsonpb.RegisterType("example.Example", nil, nil)
+	if m, _, err := fastjsonpb.NewByName("example.Example"); err != nil || !proto.Equal(m, &example.Example{}) {
+		t.Fatalf("duplicate: %v %v", err, m)
+	}
+}
@@ -0,0 +1,229 @@
+package arena
+
+import (
+	"sync/atomic"
+)
+
+const (
+	// 单个slab的最小元素个数
+	minSlabLen = 8
+	// 单个slab的最大元素个数，超过后不再翻倍
+	maxSlabLen = 4096
+	// string、bytes数据的slab大小
+	bytesSlabSize = 32 * 1024
+)
+
+var slabID int32
+
+// 为生成代码中的message类型分配slab编号，在包初始化时调用
+func NewSlabID() int {
+	return int(atomic.AddInt32(&slabID, 1)) - 1
+}
+
+// 计算下一个slab的元素个数，prev为上一个slab的元素个数
+func SlabLen(prev int) int {
+	if prev < minSlabLen {
+		return minSlabLen
+	}
+	if prev >= maxSlabLen {
+		return maxSlabLen
+	}
+	return 2 * prev
+}
+
+// 计算数组扩容后的cap
+func GrowLen(c int) int {
+	if c < minSlabLen {
+		return minSlabLen
+	}
+	return 2 * c
+}
+
+// 计算新slab的元素个数，至少容纳n个元素
+func ChunkLen(prev int, n int) int {
+	c := SlabLen(prev)
+	if c < n {
+		c = n
+	}
+	return c
+}
+
+// Arena 一次反序列化（或一组反序列化）使用的内存区域
+// message、数组、string数据从大块slab中分配，通过Free统一释放
+// 从Arena分配的message不能调用Destructor放回Pool
+// Arena不是并发安全的
+type Arena struct {
+	slabs    []interface{}
+	bytes    []byte
+	bools    []bool
+	int32s   []int32
+	int64s   []int64
+	uint32s  []uint32
+	uint64s  []uint64
+	float32s []float32
+	float64s []float64
+	strings  []string
+	byteses  [][]byte
+}
+
+func New() *Arena {
+	return &Arena{}
+}
+
+// 释放所有slab，已分配的对象在不再被引用后由GC统一回收
+func (a *Arena) Free() {
+	*a = Arena{}
+}
+
+// 获取生成代码注册的slab
+func (a *Arena) Slab(id int) interface{} {
+	if id < len(a.slabs) {
+		return a.slabs[id]
+	}
+	return nil
+}
+
+func (a *Arena) SetSlab(id int, slab interface{}) {
+	if id >= len(a.slabs) {
+		slabs := make([]interface{}, id+1)
+		copy(slabs, a.slabs)
+		a.slabs = slabs
+	}
+	a.slabs[id] = slab
+}
+
+// 分配n个字节
+func (a *Arena) Bytes(n int) []byte {
+	if cap(a.bytes)-len(a.bytes) < n {
+		size := bytesSlabSize
+		if size < n {
+			size = n
+		}
+		a.bytes = make([]byte, 0, size)
+	}
+	l := len(a.bytes)
+	a.bytes = a.bytes[:l+n]
+	return a.bytes[l : l+n : l+n]
+}
+
+// 计算数组扩容后在slab中的位置，used、size为slab已使用的元素个数和总大小，c为原数组的cap
+// 返回新数组在slab中的区间[start, end)，newSize不为0时需要先分配该大小的新slab
+func grow(used, size, c int) (start, end, newSize int) {
+	n := GrowLen(c)
+	if size-used < n {
+		return 0, n, ChunkLen(size, n)
+	}
+	return used, used + n, 0
+}
+
+// 以下AppendXxx与内置append语义一致，a为nil时直接使用append
+// 扩容时通过grow从slab分配新的空间，旧空间随Arena一起释放
+// go.mod要求go 1.17，不能使用泛型，各类型的slab字段不同，函数体有意保持相同的写法逐个实现
+
+func (a *Arena) AppendBool(s []bool, v bool) []bool {
+	if a == nil || len(s) < cap(s) {
+		return append(s, v)
+	}
+	i, j, size := grow(len(a.bools), cap(a.bools), cap(s))
+	if size > 0 {
+		a.bools = make([]bool, 0, size)
+	}
+	a.bools = a.bools[:j]
+	return append(a.bools[i:i+copy(a.bools[i:j], s):j], v)
+}
+
+func (a *Arena) AppendInt32(s []int32, v int32) []int32 {
+	if a == nil || len(s) < cap(s) {
+		return append(s, v)
+	}
+	i, j, size := grow(len(a.int32s), cap(a.int32s), cap(s))
+	if size > 0 {
+		a.int32s = make([]int32, 0, size)
+	}
+	a.int32s = a.int32s[:j]
+	return append(a.int32s[i:i+copy(a.int32s[i:j], s):j], v)
+}
+
+func (a *Arena) AppendInt64(s []int64, v int64) []int64 {
+	if a == nil || len(s) < cap(s) {
+		return append(s, v)
+	}
+	i, j, size := grow(len(a.int64s), cap(a.int64s), cap(s))
+	if size > 0 {
+		a.int64s = make([]int64, 0, size)
+	}
+	a.int64s = a.int64s[:j]
+	return append(a.int64s[i:i+copy(a.int64s[i:j], s):j], v)
+}
+
+func (a *Arena) AppendUint32(s []uint32, v uint32) []uint32 {
+	if a == nil || len(s) < cap(s) {
+		return append(s, v)
+	}
+	i, j, size := grow(len(a.uint32s), cap(a.uint32s), cap(s))
+	if size > 0 {
+		a.uint32s = make([]uint32, 0, size)
+	}
+	a.uint32s = a.uint32s[:j]
+	return append(a.uint32s[i:i+copy(a.uint32s[i:j], s):j], v)
+}
+
+func (a *Arena) AppendUint64(s []uint64, v uint64) []uint64 {
+	if a == nil || len(s) < cap(s) {
+		return append(s, v)
+	}
+	i, j, size := grow(len(a.uint64s), cap(a.uint64s), cap(s))
+	if size > 0 {
+		a.uint64s = make([]uint64, 0, size)
+	}
+	a.uint64s = a.uint64s[:j]
+	return append(a.uint64s[i:i+copy(a.uint64s[i:j], s):j], v)
+}
+
+func (a *Arena) AppendFloat32(s []float32, v float32) []float32 {
+	if a == nil || len(s) < cap(s) {
+		return append(s, v)
+	}
+	i, j, size := grow(len(a.float32s), cap(a.float32s), cap(s))
+	if size > 0 {
+		a.float32s = make([]float32, 0, size)
+	}
+	a.float32s = a.float32s[:j]
+	return append(a.float32s[i:i+copy(a.float32s[i:j], s):j], v)
+}
+
+func (a *Arena) AppendFloat64(s []float64, v float64) []float64 {
+	if a == nil || len(s) < cap(s) {
+		return append(s, v)
+	}
+	i, j, size := grow(len(a.float64s), cap(a.float64s), cap(s))
+	if size > 0 {
+		a.float64s = make([]float64, 0, size)
+	}
+	a.float64s = a.float64s[:j]
+	return append(a.float64s[i:i+copy(a.float64s[i:j], s):j], v)
+}
+
+func (a *Arena) AppendString(s []string, v string) []string {
+	if a == nil || len(s) < cap(s) {
+		return append(s, v)
+	}
+	i, j, size := grow(len(a.strings), cap(a.strings), cap(s))
+	if size > 0 {
+		a.strings = make([]string, 0, size)
+	}
+	a.strings = a.strings[:j]
+	return append(a.strings[i:i+copy(a.strings[i:j], s):j], v)
+}
+
+func (a *Arena) AppendBytes(s [][]byte, v []byte) [][]byte {
+	if a == nil || len(s) < cap(s) {
+		return append(s, v)
+	}
+	i, j, size := grow(len(a.byteses), cap(a.byteses), cap(s))
+	if size > 0 {
+		a.byteses = make([][]byte, 0, size)
+	}
+	a.byteses = a.byteses[:j]
+	return append(a.byteses[i:i+copy(a.byteses[i:j], s):j], v)
+}
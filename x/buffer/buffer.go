@@ -2,33 +2,234 @@ package buffer
 
 import (
 	"encoding/base64"
+	"errors"
+	"math"
+	"math/bits"
 	"reflect"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"unicode/utf16"
 	"unicode/utf8"
 	"unsafe"
 )
 
-var BufPool sync.Pool
+const (
+	// 最小的size class为1<<minClassShift
+	minClassShift = 6
+	// 最大的size class为1<<maxClassShift
+	maxClassShift = 30
+	numClasses    = maxClassShift - minClassShift + 1
+	// 小于该值时扩容翻倍，否则每次扩容25%
+	growThreshold = 256 * 1024
+)
+
+var (
+	// 按cap划分的Pool，第i个Pool中Buffer的cap在[1<<(minClassShift+i), 1<<(minClassShift+i+1))
+	pools [numClasses]sync.Pool
+	// New返回的Buffer的初始cap
+	initialSize int64 = 512
+	// cap超过该值的Buffer不放回Pool
+	maxRetainSize int64 = 1 << 20
+
+	// Deprecated: 使用New、Put获取和归还Buffer。
+	// 保留以兼容旧代码，放入BufPool的Buffer在New时仍会被复用
+	BufPool sync.Pool
+)
+
+// 设置New返回的Buffer的初始cap
+func SetInitialSize(n int) {
+	atomic.StoreInt64(&initialSize, int64(n))
+}
+
+// 设置放回Pool的Buffer的最大cap，超过的Buffer直接丢弃
+func SetMaxRetainSize(n int) {
+	atomic.StoreInt64(&maxRetainSize, int64(n))
+}
+
+// 严格模式下string包含非法UTF-8时记录的错误
+var ErrInvalidUTF8 = errors.New("invalid UTF-8 in string")
 
-// TODO 动态扩容
 type Buffer struct {
 	buf []byte
+	// 为true时非法UTF-8记录为错误，否则替换为\ufffd
+	strictUTF8 bool
+	err        error
+	// 为true时map按key排序输出
+	deterministic bool
+	// 为true时按字段号输出字段，默认按声明顺序
+	numberOrder bool
+	// 为true时转义<、>、&，可直接嵌入HTML
+	escapeHTML bool
+	// 为true时非ASCII字符转义为\uXXXX
+	escapeASCII bool
+	// 为true时字段名使用proto中定义的名字
+	useProtoNames bool
+	// 为true时enum输出数值
+	useEnumNumbers bool
+	// 为true时输出零值字段
+	emitUnpopulated bool
+	// 为true时64位整数输出为字符串
+	quoteInt64 bool
+	// 为true时浮点数的格式与protojson一致
+	protojsonFloat bool
 }
 
 func New() *Buffer {
+	return NewSize(int(atomic.LoadInt64(&initialSize)))
+}
+
+// 获取cap不小于n的Buffer，只从n对应的size class中获取
+func NewSize(n int) *Buffer {
+	c := sizeClass(n)
+	if c < numClasses && (1<<(minClassShift+c)) < n {
+		// n不是2的幂时，当前class中的Buffer可能不够大
+		c++
+	}
+	if c < numClasses {
+		if v := pools[c].Get(); v != nil {
+			b := v.(*Buffer)
+			b.reset()
+			return b
+		}
+		n = 1 << (minClassShift + c)
+	}
 	if v := BufPool.Get(); v != nil {
 		b := v.(*Buffer)
-		b.reset()
-		return b
+		if cap(b.buf) >= n {
+			b.reset()
+			return b
+		}
+		Put(b)
 	}
 	return &Buffer{
-		buf: []byte(""),
+		buf: make([]byte, 0, n),
 	}
 }
 
+// 将Buffer放回Pool，调用后不能再使用b及b.Bytes()返回的数据
+func Put(b *Buffer) {
+	n := cap(b.buf)
+	// 小于最小size class的Buffer不满足New对cap的保证，直接丢弃
+	if n < 1<<minClassShift || int64(n) > atomic.LoadInt64(&maxRetainSize) {
+		return
+	}
+	if c := sizeClass(n); c < numClasses {
+		pools[c].Put(b)
+	}
+}
+
+// 返回n所在的size class，满足1<<(minClassShift+class) <= n
+func sizeClass(n int) int {
+	if n < 1<<minClassShift {
+		return 0
+	}
+	return bits.Len(uint(n)) - 1 - minClassShift
+}
+
 func (b *Buffer) reset() {
 	b.buf = b.buf[:0]
+	b.strictUTF8 = false
+	b.err = nil
+	b.deterministic = false
+	b.numberOrder = false
+	b.escapeHTML = false
+	b.escapeASCII = false
+	b.useProtoNames = false
+	b.useEnumNumbers = false
+	b.emitUnpopulated = false
+	b.quoteInt64 = false
+	b.protojsonFloat = false
+}
+
+// 设置string中非法UTF-8的处理方式
+func (b *Buffer) SetStrictUTF8(strict bool) {
+	b.strictUTF8 = strict
+}
+
+// 设置是否按key排序输出map
+func (b *Buffer) SetDeterministic(deterministic bool) {
+	b.deterministic = deterministic
+}
+
+func (b *Buffer) Deterministic() bool {
+	return b.deterministic
+}
+
+// 设置是否按字段号输出字段
+func (b *Buffer) SetNumberOrder(numberOrder bool) {
+	b.numberOrder = numberOrder
+}
+
+func (b *Buffer) NumberOrder() bool {
+	return b.numberOrder
+}
+
+// 设置是否转义<、>、&，与encoding/json的HTMLEscape一致
+func (b *Buffer) SetEscapeHTML(escape bool) {
+	b.escapeHTML = escape
+}
+
+// 设置是否将非ASCII字符转义为\uXXXX，BMP以外的字符使用代理对
+func (b *Buffer) SetEscapeASCII(escape bool) {
+	b.escapeASCII = escape
+}
+
+// 设置字段名是否使用proto中定义的名字，默认使用json名字（lowerCamelCase）
+func (b *Buffer) SetUseProtoNames(use bool) {
+	b.useProtoNames = use
+}
+
+func (b *Buffer) UseProtoNames() bool {
+	return b.useProtoNames
+}
+
+// 设置enum是否输出数值，默认输出名字
+func (b *Buffer) SetUseEnumNumbers(use bool) {
+	b.useEnumNumbers = use
+}
+
+func (b *Buffer) UseEnumNumbers() bool {
+	return b.useEnumNumbers
+}
+
+// 设置是否输出零值字段，未设置的message输出null，oneof不受影响
+func (b *Buffer) SetEmitUnpopulated(emit bool) {
+	b.emitUnpopulated = emit
+}
+
+func (b *Buffer) EmitUnpopulated() bool {
+	return b.emitUnpopulated
+}
+
+// 设置64位整数是否输出为字符串，与protojson一致，避免JavaScript等语言丢失精度
+func (b *Buffer) SetQuoteInt64(quote bool) {
+	b.quoteInt64 = quote
+}
+
+func (b *Buffer) QuoteInt64() bool {
+	return b.quoteInt64
+}
+
+// 设置浮点数的格式是否与protojson一致，默认使用strconv的'f'格式
+func (b *Buffer) SetProtojsonFloat(protojson bool) {
+	b.protojsonFloat = protojson
+}
+
+// 记录序列化过程中的错误，只保留第一个
+func (b *Buffer) SetErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// 返回序列化过程中记录的第一个错误
+func (b *Buffer) Err() error {
+	return b.err
+}
+
+func (b *Buffer) Len() int {
+	return len(b.buf)
 }
 
 func (b *Buffer) WriteStr(data string) (int, error) {
@@ -60,25 +261,70 @@ func (b *Buffer) WriteUint64(data uint64) (int, error) {
 }
 
 func (b *Buffer) WriteFloat32(data float32) (int, error) {
+	if b.protojsonFloat {
+		return b.writeFloat(float64(data), 32)
+	}
 	return b.WriteStr(strconv.FormatFloat(float64(data), 'f', -1, 32))
 }
 
 func (b *Buffer) WriteFloat64(data float64) (int, error) {
+	if b.protojsonFloat {
+		return b.writeFloat(data, 64)
+	}
 	return b.WriteStr(strconv.FormatFloat(data, 'f', -1, 64))
 }
 
+// 与protojson一致：NaN、Infinity输出为字符串，绝对值小于1e-6或不小于1e21时使用指数形式
+func (b *Buffer) writeFloat(data float64, bitSize int) (int, error) {
+	switch {
+	case math.IsNaN(data):
+		return b.WriteStr(`"NaN"`)
+	case math.IsInf(data, 1):
+		return b.WriteStr(`"Infinity"`)
+	case math.IsInf(data, -1):
+		return b.WriteStr(`"-Infinity"`)
+	}
+	n := len(b.buf)
+	f := byte('f')
+	if abs := math.Abs(data); abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			f = 'e'
+		}
+	}
+	b.buf = strconv.AppendFloat(b.buf, data, f, -1, bitSize)
+	if f == 'e' {
+		// e-07转换为e-7
+		l := len(b.buf)
+		if l >= 4 && b.buf[l-4] == 'e' && b.buf[l-3] == '-' && b.buf[l-2] == '0' {
+			b.buf[l-2] = b.buf[l-1]
+			b.buf = b.buf[:l-1]
+		}
+	}
+	return len(b.buf) - n, nil
+}
+
 // 区别于Write，这里将[]byte序列化到json，需要进行base64编码
+// 写入带引号的标准base64编码，直接编码到buf中
 func (b *Buffer) WriteBytes(data []byte) (int, error) {
-	return b.WriteStr(`"` + base64.StdEncoding.EncodeToString(data) + `"`)
+	n := base64.StdEncoding.EncodedLen(len(data))
+	m, err := b.grow(n + 2)
+	if err != nil {
+		return 0, err
+	}
+	b.buf[m] = '"'
+	base64.StdEncoding.Encode(b.buf[m+1:m+1+n], data)
+	b.buf[m+1+n] = '"'
+	return n + 2, nil
 }
 
-func (b *Buffer) WriteByte(data byte) (int, error) {
+// 实现io.ByteWriter，返回值由(int, error)改为error以满足go vet的方法签名检查
+func (b *Buffer) WriteByte(data byte) error {
 	m, err := b.grow(1)
 	if err == nil {
 		b.buf[m] = data
-		return 1, nil
 	}
-	return 0, nil
+	return err
 }
 
 func (b *Buffer) Write(data []byte) (int, error) {
@@ -100,24 +346,39 @@ func (b *Buffer) Bytes() []byte {
 	return b.buf
 }
 
+// 扩容策略：cap小于growThreshold时翻倍，之后每次增加25%，且至少能容纳本次写入
+// 翻倍减少小Buffer的扩容次数，大Buffer按比例增长避免一次性浪费过多内存
 func (b *Buffer) grow(n int) (int, error) {
 	l := len(b.buf)
 	if l+n > cap(b.buf) {
-		// TODO grow
-		buf := make([]byte, 2*(l+n))
-		copy(buf, b.buf)
-		b.buf = buf
+		b.realloc(l + n)
 	}
 	b.buf = b.buf[:l+n]
 	return l, nil
 }
 
-func (b *Buffer) Grow(n int) {
-	buf := make([]byte, n)
+func (b *Buffer) realloc(need int) {
+	c := cap(b.buf)
+	if c < growThreshold {
+		c *= 2
+	} else {
+		c += c / 4
+	}
+	if c < need {
+		c = need
+	}
+	buf := make([]byte, len(b.buf), c)
 	copy(buf, b.buf)
 	b.buf = buf
 }
 
+// 保证至少还能写入n个字节而不扩容
+func (b *Buffer) Grow(n int) {
+	if len(b.buf)+n > cap(b.buf) {
+		b.realloc(len(b.buf) + n)
+	}
+}
+
 func Bytes2Str(b []byte) string {
 	return *(*string)(unsafe.Pointer(&b))
 }
@@ -133,6 +394,15 @@ func Str2Bytes(s string) (b []byte) {
 
 const hex = "0123456789abcdef"
 
+// 在safeSet的基础上转义<、>、&
+var htmlSafeSet = func() [utf8.RuneSelf]bool {
+	set := safeSet
+	set['<'] = false
+	set['>'] = false
+	set['&'] = false
+	return set
+}()
+
 var safeSet = [utf8.RuneSelf]bool{
 	' ':      true,
 	'!':      true,
@@ -239,10 +509,14 @@ func (b *Buffer) WriteStringWithQuote(s string) {
 }
 
 func (b *Buffer) WriteString(s string) {
+	set := &safeSet
+	if b.escapeHTML {
+		set = &htmlSafeSet
+	}
 	start := 0
 	for i := 0; i < len(s); {
 		if c := s[i]; c < utf8.RuneSelf {
-			if safeSet[c] {
+			if set[c] {
 				i++
 				continue
 			}
@@ -270,6 +544,9 @@ func (b *Buffer) WriteString(s string) {
 		}
 		c, size := utf8.DecodeRuneInString(s[i:])
 		if c == utf8.RuneError && size == 1 {
+			if b.strictUTF8 && b.err == nil {
+				b.err = ErrInvalidUTF8
+			}
 			if start < i {
 				b.WriteStr(s[start:i])
 			}
@@ -288,9 +565,32 @@ func (b *Buffer) WriteString(s string) {
 			start = i
 			continue
 		}
+		if b.escapeASCII {
+			if start < i {
+				b.WriteStr(s[start:i])
+			}
+			if r1, r2 := utf16.EncodeRune(c); r1 != utf8.RuneError {
+				b.writeRuneEscape(r1)
+				b.writeRuneEscape(r2)
+			} else {
+				b.writeRuneEscape(c)
+			}
+			i += size
+			start = i
+			continue
+		}
 		i += size
 	}
 	if start < len(s) {
 		b.WriteStr(s[start:])
 	}
 }
+
+// 写入\uXXXX形式的转义
+func (b *Buffer) writeRuneEscape(r rune) {
+	b.WriteStr(`\u`)
+	b.WriteByte(hex[r>>12&0xF])
+	b.WriteByte(hex[r>>8&0xF])
+	b.WriteByte(hex[r>>4&0xF])
+	b.WriteByte(hex[r&0xF])
+}
@@ -0,0 +1,105 @@
+package buffer
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// JCS不能表示NaN、Infinity
+var ErrCanonicalNumber = errors.New("NaN and Infinity are not allowed in canonical JSON")
+
+// 按RFC 8785（JCS）写入数字，与ECMAScript的Number.prototype.toString一致
+func (b *Buffer) WriteCanonicalNumber(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return ErrCanonicalNumber
+	}
+	if f == 0 {
+		// -0输出为0
+		b.WriteByte('0')
+		return nil
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+	m := len(b.buf)
+	b.buf = strconv.AppendFloat(b.buf, f, format, -1, 64)
+	if format == 'e' {
+		// 指数部分去掉前导0，如1e-07转换为1e-7
+		n := len(b.buf)
+		if n-m >= 4 && b.buf[n-4] == 'e' && b.buf[n-3] == '-' && b.buf[n-2] == '0' {
+			b.buf[n-2] = b.buf[n-1]
+			b.buf = b.buf[:n-1]
+		}
+	}
+	return nil
+}
+
+// 按RFC 8785（JCS）写入带引号的string，只转义引号、反斜杠和控制字符，其他字符原样输出
+// s需要是合法的UTF-8
+func (b *Buffer) WriteCanonicalString(s string) {
+	b.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 0x20 && c != '"' && c != '\\' {
+			continue
+		}
+		if start < i {
+			b.WriteStr(s[start:i])
+		}
+		b.WriteByte('\\')
+		switch c {
+		case '"', '\\':
+			b.WriteByte(c)
+		case '\b':
+			b.WriteByte('b')
+		case '\f':
+			b.WriteByte('f')
+		case '\n':
+			b.WriteByte('n')
+		case '\r':
+			b.WriteByte('r')
+		case '\t':
+			b.WriteByte('t')
+		default:
+			b.WriteStr(`u00`)
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xF])
+		}
+		start = i + 1
+	}
+	if start < len(s) {
+		b.WriteStr(s[start:])
+	}
+	b.WriteByte('"')
+}
+
+// 按UTF-16编码单元比较两个string，JCS中对象的key按该顺序排序
+func LessUTF16(a, b string) bool {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			a1, a2 := utf16Units(ra)
+			b1, b2 := utf16Units(rb)
+			if a1 != b1 {
+				return a1 < b1
+			}
+			return a2 < b2
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return a == "" && b != ""
+}
+
+// 返回rune的UTF-16编码单元，BMP内的字符第二个单元为0
+func utf16Units(r rune) (rune, rune) {
+	if r < 0x10000 {
+		return r, 0
+	}
+	r -= 0x10000
+	return 0xd800 + (r>>10)&0x3ff, 0xdc00 + r&0x3ff
+}
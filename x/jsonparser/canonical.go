@@ -0,0 +1,149 @@
+package jsonparser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer"
+)
+
+// JCS中数字为IEEE 754双精度浮点数，不能精确表示的整数返回该错误
+var ErrCanonicalInteger = errors.New("integer cannot be represented exactly in canonical JSON")
+
+// 将data转换成RFC 8785（JCS）规范形式写入buf
+// 对象的key按UTF-16编码单元排序，数字按ECMAScript的格式输出，string只做最少的转义
+// 对象中出现重复key、整数超出float64的精确表示范围时返回错误
+func Canonicalize(buf *buffer.Buffer, data []byte) (err error) {
+	p := New(data)
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+	p.canonical(buf)
+	p.End()
+	return nil
+}
+
+// 对象的成员，value为规范形式在临时Buffer中的位置
+type canonicalMember struct {
+	key        string
+	start, end int
+}
+
+// 解析一个值并按规范形式写入buf，错误通过panic返回
+func (p *Parser) canonical(buf *buffer.Buffer) {
+	// 尝试获取新token，需要清空上一次token信息
+	if p.token.kind != tokenUnknown {
+		panic("system exception")
+	}
+
+	p.getToken()
+
+	switch p.token.kind {
+	case tokenBool:
+		buf.WriteBool(p.Bol())
+		return
+	case tokenString:
+		buf.WriteCanonicalString(p.Str())
+		return
+	case tokenNumber:
+		p.canonicalNumber(buf)
+		return
+	case tokenNull:
+		p.Null()
+		buf.WriteStr("null")
+		return
+	case tokenSymbol:
+		p.reset()
+		if p.token.symbol == '{' {
+			p.canonicalObj(buf)
+			return
+		} else if p.token.symbol == '[' {
+			p.canonicalArr(buf)
+			return
+		}
+	}
+
+	panic("unknown token")
+}
+
+func (p *Parser) canonicalNumber(buf *buffer.Buffer) {
+	raw := p.token.raw
+	f := p.Number()
+	start := buf.Len()
+	if err := buf.WriteCanonicalNumber(f); err != nil {
+		panic(err)
+	}
+	if bytes.IndexAny(raw, ".eE") < 0 && !sameInteger(raw, buf.Bytes()[start:]) {
+		p.errAt(ErrCanonicalInteger)
+	}
+}
+
+// 整数raw与其规范形式out的数值是否相同，不同说明转换成float64时丢失了精度（如2^53+1）
+// 只比较数值，1000000000000000000000的规范形式1e+21是合法的
+func sameInteger(raw, out []byte) bool {
+	// 不超过15位的整数小于2^53，一定可以精确表示
+	if len(raw) <= 15 {
+		return true
+	}
+	a, ok1 := new(big.Rat).SetString(string(raw))
+	b, ok2 := new(big.Rat).SetString(string(out))
+	return ok1 && ok2 && a.Cmp(b) == 0
+}
+
+func (p *Parser) canonicalObj(buf *buffer.Buffer) {
+	vals := buffer.New()
+	defer buffer.Put(vals)
+	var members []canonicalMember
+	for !p.IsSymbol('}') {
+		key := p.Str()
+		p.AssertSymbol(':')
+		start := vals.Len()
+		p.canonical(vals)
+		members = append(members, canonicalMember{key: key, start: start, end: vals.Len()})
+		p.AssertSymbol(',')
+	}
+	// 处理空对象情况
+	p.AssertSymbol(',')
+	p.Symbol('}')
+
+	sort.Slice(members, func(i, j int) bool { return buffer.LessUTF16(members[i].key, members[j].key) })
+	b := vals.Bytes()
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			// 排序后重复的key相邻，JCS不允许重复key
+			if m.key == members[i-1].key {
+				panic(fmt.Errorf("%w %q", ErrDuplicateKey, m.key))
+			}
+			buf.WriteByte(',')
+		}
+		buf.WriteCanonicalString(m.key)
+		buf.WriteByte(':')
+		buf.Write(b[m.start:m.end])
+	}
+	buf.WriteByte('}')
+}
+
+func (p *Parser) canonicalArr(buf *buffer.Buffer) {
+	buf.WriteByte('[')
+	for i := 0; !p.IsSymbol(']'); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		p.canonical(buf)
+		p.AssertSymbol(',')
+	}
+	// 处理空数组情况
+	p.AssertSymbol(',')
+	p.Symbol(']')
+	buf.WriteByte(']')
+}
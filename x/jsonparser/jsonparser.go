@@ -1,9 +1,16 @@
 package jsonparser
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
 	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
 
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/arena"
 	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer"
 )
 
@@ -13,6 +20,36 @@ const (
 	EnumNumber
 )
 
+// 未设置MaxDepth时的最大嵌套深度
+const DefaultMaxDepth = 10000
+
+var (
+	ErrMaxDepth     = errors.New("exceeded max nesting depth")
+	ErrMaxSize      = errors.New("exceeded max input size")
+	ErrMaxStringLen = errors.New("exceeded max string length")
+	ErrMaxElements  = errors.New("exceeded max number of elements")
+	ErrDuplicateKey = errors.New("duplicate key")
+	// 与buffer共用同一个错误，序列化、反序列化可以统一用errors.Is判断
+	ErrInvalidUTF8  = buffer.ErrInvalidUTF8
+	ErrBase64       = errors.New("invalid base64 data")
+	ErrNumber       = errors.New("invalid number")
+	ErrOverflow     = errors.New("number out of range")
+	ErrNotInteger   = errors.New("number is not an integer")
+	ErrUnknownField = errors.New("unknown field")
+)
+
+// 对象中出现重复key（包括同一个oneof的多个成员）时的处理方式
+type DuplicatePolicy int
+
+const (
+	// 保留最后一个
+	DuplicateLastWins DuplicatePolicy = iota
+	// 保留第一个，忽略后续的值
+	DuplicateFirstWins
+	// 返回错误
+	DuplicateError
+)
+
 type tokenKind byte
 
 const (
@@ -29,32 +66,109 @@ type token struct {
 	bol    bool
 	raw    []byte
 	symbol byte
+	// raw是否为转义处理后的数据，此时raw指向scratch而不是输入数据
+	escaped bool
+}
+
+// 解析选项
+type Options struct {
+	// 为true时Str、Bytes直接引用输入数据，不再拷贝
+	// 调用方需保证输入数据在解析结果使用期间不被修改
+	ZeroCopy bool
+	// 不为nil时message、数组、string数据从Arena分配
+	Arena *arena.Arena
+	// 最大嵌套深度，0表示使用DefaultMaxDepth，小于0表示不限制
+	MaxDepth int
+	// 输入数据的最大字节数，0表示不限制
+	MaxSize int
+	// string的最大字节数（转义处理前），0表示不限制
+	MaxStringLen int
+	// 单个数组或对象的最大元素个数，包括repeated字段和map，0表示不限制
+	MaxElements int
+	// 重复key的处理方式，默认保留最后一个
+	Duplicate DuplicatePolicy
+	// 为true时string包含非法UTF-8返回错误
+	StrictUTF8 bool
+	// 为true时Unknown对未知字段返回错误，默认跳过
+	RejectUnknown bool
+	// 跳过或保留未知字段时调用，path为所在对象的路径，raw为value的原始数据，只在回调期间有效
+	OnUnknownField func(path, key string, raw []byte)
+	// 为true时合并到已有数据，repeated字段追加，map按key合并，message递归合并
+	Merge bool
+	// 为true时SkipNull跳过null，字段作为未设置处理，默认null只能用于未知字段
+	NullAsUnset bool
+}
+
+// 当前所在的对象或数组
+type frame struct {
+	obj bool
+	// 已经出现的逗号个数
+	n int
+	// 对象中下一个string为key
+	wantKey bool
+	// 对象中最近的key，用于错误信息中的路径
+	key string
 }
 
 type Parser struct {
 	data   []byte
 	off    int
-	token  *token
+	token  token
 	assert byte
+	opts   Options
+	// 转义字符串的临时空间，解析过程中复用
+	scratch []byte
+	// 拷贝string、bytes使用的空间，一次解析最多分配一次
+	strs []byte
+	// 嵌套的对象、数组，解析过程中复用
+	frames []frame
 }
 
 func New(data []byte) *Parser {
-	return &Parser{
-		data: data,
-		token: &token{
-			kind: tokenUnknown,
-		},
+	p := &Parser{}
+	p.Reset(data)
+	return p
+}
+
+// 重置解析器以复用，包括解析失败后残留的状态
+func (p *Parser) Reset(data []byte) {
+	p.data = data
+	p.off = 0
+	p.assert = 0
+	p.token = token{
+		kind: tokenUnknown,
 	}
+	// 已经返回给调用方的数据不能复用
+	p.strs = nil
+	p.frames = p.frames[:0]
+}
+
+func (p *Parser) SetOptions(opts Options) {
+	p.opts = opts
+}
+
+// 供生成代码判断是否合并到已有数据
+func (p *Parser) Merge() bool {
+	return p.opts.Merge
+}
+
+// 供生成代码从Arena分配对象，未设置时返回nil
+func (p *Parser) Arena() *arena.Arena {
+	return p.opts.Arena
 }
 
 // 获取token
 func (p *Parser) getToken() {
+	if p.off == 0 && p.opts.MaxSize > 0 && len(p.data) > p.opts.MaxSize {
+		p.errAt(ErrMaxSize)
+	}
 	for _, c := range p.data[p.off:] {
 		switch c {
 		case '{', '[':
 			if p.assert != 0 {
 				p.syntaxErr()
 			}
+			p.push(c == '{')
 			p.token.kind = tokenSymbol
 			p.token.symbol = c
 			p.off++
@@ -63,6 +177,7 @@ func (p *Parser) getToken() {
 			if p.assert != ',' {
 				p.syntaxErr()
 			}
+			p.pop(c == '}')
 			p.AssertSymbol(0)
 			p.token.kind = tokenSymbol
 			p.token.symbol = c
@@ -77,11 +192,17 @@ func (p *Parser) getToken() {
 			p.off++
 			p.token.kind = tokenString
 			p.getString()
+			if l := len(p.frames); l > 0 && p.frames[l-1].wantKey {
+				p.setKey(&p.frames[l-1])
+			}
 			return
 		case ':', ',':
 			if p.assert != c {
 				p.syntaxErr()
 			}
+			if c == ',' {
+				p.next()
+			}
 			p.reset()
 			p.AssertSymbol(0)
 			p.off++
@@ -117,122 +238,249 @@ func (p *Parser) getToken() {
 			p.syntaxErr()
 		}
 	}
+	// 输入不完整
+	p.syntaxErr()
 }
 
-func (p *Parser) getString() {
-	findEscape, l := p.check()
-	if findEscape {
-		p.token.raw = make([]byte, 0, 2*l)
+// 进入对象或数组
+func (p *Parser) push(obj bool) {
+	max := p.opts.MaxDepth
+	if max == 0 {
+		max = DefaultMaxDepth
 	}
-	i := p.off
-Switch:
-	for ; i < len(p.data); i++ {
-		switch p.data[i] {
-		case '\\':
-			i++
-			p.escape(&i)
-			break
-		case '"':
-			break Switch
-		default:
-			if findEscape {
-				p.token.raw = append(p.token.raw, p.data[i])
-			}
-		}
+	if max > 0 && len(p.frames) >= max {
+		p.errAt(ErrMaxDepth)
+	}
+	p.frames = append(p.frames, frame{obj: obj, wantKey: obj})
+}
+
+// 离开对象或数组
+func (p *Parser) pop(obj bool) {
+	l := len(p.frames)
+	if l == 0 || p.frames[l-1].obj != obj {
+		p.syntaxErr()
+	}
+	p.frames = p.frames[:l-1]
+}
+
+// 对象或数组中出现下一个元素
+func (p *Parser) next() {
+	l := len(p.frames)
+	if l == 0 {
+		p.syntaxErr()
 	}
-	if !findEscape {
-		p.token.raw = p.data[p.off:i]
+	f := &p.frames[l-1]
+	f.n++
+	f.wantKey = f.obj
+	if p.opts.MaxElements > 0 && f.n >= p.opts.MaxElements {
+		p.errAt(ErrMaxElements)
 	}
-	// 跳过"
-	p.off = i + 1
 }
 
-func (p *Parser) check() (bool, int) {
-	findEscape := false
+func (p *Parser) getString() {
 	i := p.off
-	l := 0
-Switch:
-	for ; i < len(p.data); i, l = i+1, l+1 {
-		switch p.data[i] {
-		case '\\':
-			findEscape = true
+	// 没有转义字符时直接引用输入数据
+	for i < len(p.data) {
+		c := p.data[i]
+		if c == '"' {
+			p.checkStringLen(i)
+			p.token.escaped = false
+			p.token.raw = p.data[p.off:i]
+			// 跳过"
+			p.off = i + 1
+			return
+		}
+		if c == '\\' {
+			break
+		}
+		if c < 0x20 {
+			p.off = i
+			p.syntaxErr()
+		}
+		i++
+	}
+	raw := append(p.scratch[:0], p.data[p.off:i]...)
+	for i < len(p.data) {
+		c := p.data[i]
+		switch {
+		case c == '"':
+			p.checkStringLen(i)
+			p.scratch = raw
+			p.token.escaped = true
+			p.token.raw = raw
+			// 跳过"
+			p.off = i + 1
+			return
+		case c == '\\':
+			raw, i = p.escape(raw, i+1)
+		case c < 0x20:
+			p.off = i
+			p.syntaxErr()
+		default:
+			raw = append(raw, c)
 			i++
-		case '"':
-			break Switch
 		}
 	}
-	return findEscape, l
+	// 缺少结束的"
+	p.off = i
+	p.syntaxErr()
+}
+
+// end为结束"的位置
+func (p *Parser) checkStringLen(end int) {
+	if p.opts.MaxStringLen > 0 && end-p.off > p.opts.MaxStringLen {
+		p.errAt(ErrMaxStringLen)
+	}
 }
 
-func (p *Parser) escape(off *int) {
-	switch p.data[*off] {
-	case '"', '\\', '/', '\'':
-		p.token.raw = append(p.token.raw, p.data[*off])
+// 处理转义字符，i为\后一个字符的位置，返回处理后的数据以及下一个字符的位置
+func (p *Parser) escape(raw []byte, i int) ([]byte, int) {
+	if i >= len(p.data) {
+		p.off = i
+		p.syntaxErr()
+	}
+	switch p.data[i] {
+	case '"', '\\', '/':
+		raw = append(raw, p.data[i])
 	case 'f':
-		p.token.raw = append(p.token.raw, '\f')
+		raw = append(raw, '\f')
 	case 't':
-		p.token.raw = append(p.token.raw, '\t')
+		raw = append(raw, '\t')
 	case 'b':
-		p.token.raw = append(p.token.raw, '\b')
+		raw = append(raw, '\b')
 	case 'r':
-		p.token.raw = append(p.token.raw, '\r')
+		raw = append(raw, '\r')
 	case 'n':
-		p.token.raw = append(p.token.raw, '\n')
+		raw = append(raw, '\n')
 	case 'u':
-		// TODO unicode
+		r, ok := p.hex4(i + 1)
+		if !ok {
+			p.off = i
+			p.syntaxErr()
+		}
+		i += 5
+		if utf16.IsSurrogate(r) {
+			// 代理对由两个\u组成，单独出现的代理项替换为U+FFFD
+			r2, ok := rune(0), false
+			if i+1 < len(p.data) && p.data[i] == '\\' && p.data[i+1] == 'u' {
+				r2, ok = p.hex4(i + 2)
+			}
+			if r = utf16.DecodeRune(r, r2); ok && r != utf8.RuneError {
+				i += 6
+			} else {
+				r = utf8.RuneError
+			}
+		}
+		var b [utf8.UTFMax]byte
+		n := utf8.EncodeRune(b[:], r)
+		return append(raw, b[:n]...), i
 	default:
+		p.off = i
 		p.syntaxErr()
 	}
+	return raw, i + 1
 }
 
-func (p *Parser) getTrue() {
-	if p.off+3 > len(p.data) ||
-		p.data[p.off+1] != 'r' ||
-		p.data[p.off+2] != 'u' ||
-		p.data[p.off+3] != 'e' {
-		p.syntaxErr()
+// 解析4位16进制数
+func (p *Parser) hex4(i int) (rune, bool) {
+	if i+4 > len(p.data) {
+		return 0, false
 	}
+	var r rune
+	for _, c := range p.data[i : i+4] {
+		switch {
+		case '0' <= c && c <= '9':
+			c = c - '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return 0, false
+		}
+		r = r<<4 | rune(c)
+	}
+	return r, true
+}
+
+func (p *Parser) getTrue() {
+	p.keyword("true")
 	p.token.bol = true
-	p.off += 4
 }
 
 func (p *Parser) getFalse() {
-	if p.off+4 > len(p.data) ||
-		p.data[p.off+1] != 'a' ||
-		p.data[p.off+2] != 'l' ||
-		p.data[p.off+3] != 's' ||
-		p.data[p.off+4] != 'e' {
+	p.keyword("false")
+	p.token.bol = false
+}
+
+func (p *Parser) getNull() {
+	p.keyword("null")
+}
+
+func (p *Parser) keyword(w string) {
+	if len(p.data)-p.off < len(w) || string(p.data[p.off:p.off+len(w)]) != w {
 		p.syntaxErr()
 	}
-	p.token.bol = false
-	p.off += 5
+	p.off += len(w)
 }
 
+// 按照RFC 8259解析数字：-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
 func (p *Parser) getNumber() {
-	i := p.off
-Switch:
-	for ; i < len(p.data); i++ {
-		switch p.data[i] {
-		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '.', 'e', 'E', '+', '-':
-		default:
-			break Switch
-		}
+	i, ok := scanNumber(p.data, p.off)
+	if !ok {
+		p.off = i
+		p.syntaxErr()
 	}
+	p.token.escaped = false
 	p.token.raw = p.data[p.off:i]
 	p.off = i
 }
 
-func (p *Parser) getNull() {
-	if p.off+3 > len(p.data) ||
-		p.data[p.off+1] != 'u' ||
-		p.data[p.off+2] != 'l' ||
-		p.data[p.off+3] != 'l' {
-		p.syntaxErr()
+// 按RFC 8259的语法扫描从i开始的数字，返回结束位置，不合法时返回出错的位置
+func scanNumber(b []byte, i int) (int, bool) {
+	if i < len(b) && b[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(b) && b[i] == '0':
+		i++
+	case i < len(b) && '1' <= b[i] && b[i] <= '9':
+		i = digits(b, i)
+	default:
+		return i, false
+	}
+	if i < len(b) && b[i] == '.' {
+		i++
+		if i >= len(b) || !isDigit(b[i]) {
+			return i, false
+		}
+		i = digits(b, i)
+	}
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			i++
+		}
+		if i >= len(b) || !isDigit(b[i]) {
+			return i, false
+		}
+		i = digits(b, i)
+	}
+	return i, true
+}
+
+func digits(b []byte, i int) int {
+	for i < len(b) && isDigit(b[i]) {
+		i++
 	}
-	p.off += 4
+	return i
 }
 
-// 解析string，包括key，value
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// 解析string
 func (p *Parser) Str() string {
 	if p.token.kind == tokenUnknown {
 		p.getToken()
@@ -241,6 +489,112 @@ func (p *Parser) Str() string {
 		p.syntaxErr()
 	}
 	p.reset()
+	if p.opts.StrictUTF8 && !utf8.Valid(p.token.raw) {
+		panic(fmt.Errorf("%w: near col %d", ErrInvalidUTF8, p.off))
+	}
+	return buffer.Bytes2Str(p.stable(p.token.raw))
+}
+
+// 记录对象当前的key，转义后的数据在scratch中会被复用，需要拷贝
+// 路径只在报告未知字段时使用，未设置RejectUnknown、OnUnknownField时不拷贝
+func (p *Parser) setKey(f *frame) {
+	f.wantKey = false
+	if !p.token.escaped {
+		f.key = buffer.Bytes2Str(p.token.raw)
+	} else if p.opts.RejectUnknown || p.opts.OnUnknownField != nil {
+		f.key = string(p.token.raw)
+	} else {
+		f.key = ""
+	}
+}
+
+// 返回当前对象在文档中的路径，如msg.arr[1]，顶层对象返回空字符串
+// 只能在解析过程中使用，未设置RejectUnknown、OnUnknownField时不包含转义过的key
+func (p *Parser) Path() string {
+	var path []byte
+	for i := 0; i < len(p.frames)-1; i++ {
+		f := &p.frames[i]
+		if !f.obj {
+			path = append(path, '[')
+			path = strconv.AppendInt(path, int64(f.n), 10)
+			path = append(path, ']')
+			continue
+		}
+		if len(path) > 0 {
+			path = append(path, '.')
+		}
+		path = append(path, f.key...)
+	}
+	return string(path)
+}
+
+// 处理当前对象中的未知字段key，默认跳过其value
+func (p *Parser) Unknown(key string) {
+	if p.opts.RejectUnknown {
+		if path := p.Path(); path != "" {
+			key = path + "." + key
+		}
+		panic(fmt.Errorf("%w %q near col %d", ErrUnknownField, key, p.off))
+	}
+	if p.opts.OnUnknownField != nil {
+		p.UnknownValue(key)
+		return
+	}
+	p.PassParse()
+}
+
+// 解析对象的key，返回值只在获取下一个token前有效
+func (p *Parser) Key() string {
+	if p.token.kind == tokenUnknown {
+		p.getToken()
+	}
+	if p.token.kind != tokenString {
+		p.syntaxErr()
+	}
+	p.reset()
+	return buffer.Bytes2Str(p.token.raw)
+}
+
+// 返回解析结束后依然有效的数据
+// 非ZeroCopy模式下拷贝到strs，输入数据可以被调用方复用
+func (p *Parser) stable(raw []byte) []byte {
+	if p.opts.ZeroCopy && !p.token.escaped {
+		return raw
+	}
+	b := p.alloc(len(raw))
+	copy(b, raw)
+	return b
+}
+
+// 分配n个字节，用于保存string、bytes的解析结果
+func (p *Parser) alloc(n int) []byte {
+	if p.opts.Arena != nil {
+		return p.opts.Arena.Bytes(n)
+	}
+	if cap(p.strs)-len(p.strs) < n {
+		// 转义、base64解码后的长度不会超过原始长度，按剩余输入分配即可满足本次解析
+		p.strs = make([]byte, 0, n+len(p.data)-p.off)
+	}
+	l := len(p.strs)
+	p.strs = p.strs[:l+n]
+	// 限制cap，避免调用方append覆盖相邻数据
+	return p.strs[l : l+n : l+n]
+}
+
+// 解析map中bool类型的key
+func (p *Parser) BolKey() bool {
+	switch p.Key() {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	p.syntaxErr()
+	return false
+}
+
+// 返回最近一个token的原始数据，如map中非string类型的key，在读取下一个token之前有效
+func (p *Parser) Raw() string {
 	return buffer.Bytes2Str(p.token.raw)
 }
 
@@ -264,108 +618,188 @@ func (p *Parser) Number() float64 {
 	if p.token.kind != tokenNumber {
 		p.syntaxErr()
 	}
+	p.reset()
 	n, err := strconv.ParseFloat(buffer.Bytes2Str(p.token.raw), 64)
 	if err != nil {
-		panic(err)
+		p.errAt(ErrOverflow)
 	}
-	p.reset()
 	return n
 }
 
-// 解析int32
-func (p *Parser) Int32() int32 {
+// 获取数字token，与protojson一致兼容用字符串表示的数字
+func (p *Parser) numberToken() ([]byte, bool) {
 	if p.token.kind == tokenUnknown {
 		p.getToken()
 	}
-	if p.token.kind != tokenNumber {
+	quoted := p.token.kind == tokenString
+	if p.token.kind != tokenNumber && !quoted {
 		p.syntaxErr()
 	}
-	n, err := strconv.ParseInt(buffer.Bytes2Str(p.token.raw), 10, 32)
+	p.reset()
+	return p.token.raw, quoted
+}
+
+// 解析整数，返回绝对值和符号
+func (p *Parser) integer() (uint64, bool) {
+	raw, _ := p.numberToken()
+	n, neg, err := parseInteger(raw)
 	if err != nil {
-		panic(err)
+		p.errAt(err)
+	}
+	return n, neg
+}
+
+// 按protojson的规则将数字转换成整数
+// 允许指数形式，如1e3、1.5e1，小数部分不为0时返回ErrNotInteger
+func parseInteger(b []byte) (n uint64, neg bool, err error) {
+	if end, ok := scanNumber(b, 0); !ok || end != len(b) {
+		return 0, false, ErrNumber
+	}
+	i := 0
+	if b[i] == '-' {
+		neg = true
+		i++
+	}
+	intp := b[i:digits(b, i)]
+	i += len(intp)
+	var frac []byte
+	if i < len(b) && b[i] == '.' {
+		frac = b[i+1 : digits(b, i+1)]
+		i += 1 + len(frac)
+	}
+	exp := 0
+	if i < len(b) {
+		// 跳过e、E
+		i++
+		expNeg := b[i] == '-'
+		if b[i] == '+' || b[i] == '-' {
+			i++
+		}
+		for ; i < len(b); i++ {
+			// 指数过大时结果必然溢出或不是整数，截断避免int溢出
+			if exp < 1e9 {
+				exp = exp*10 + int(b[i]-'0')
+			}
+		}
+		if expNeg {
+			exp = -exp
+		}
+	}
+	// intp、frac连接后的数字中，point之后的部分为小数
+	point := len(intp) + exp
+	for k := range intp {
+		if k >= point && intp[k] != '0' {
+			return 0, false, ErrNotInteger
+		}
+	}
+	for k := range frac {
+		if len(intp)+k >= point && frac[k] != '0' {
+			return 0, false, ErrNotInteger
+		}
+	}
+	k := 0
+	for ; k < point && k < len(intp)+len(frac); k++ {
+		var c byte
+		if k < len(intp) {
+			c = intp[k] - '0'
+		} else {
+			c = frac[k-len(intp)] - '0'
+		}
+		if n > (math.MaxUint64-uint64(c))/10 {
+			return 0, false, ErrOverflow
+		}
+		n = n*10 + uint64(c)
+	}
+	// 指数大于小数位数时补0
+	for ; k < point && n != 0; k++ {
+		if n > math.MaxUint64/10 {
+			return 0, false, ErrOverflow
+		}
+		n *= 10
+	}
+	return n, neg, nil
+}
+
+// 解析int32
+func (p *Parser) Int32() int32 {
+	n, neg := p.integer()
+	if neg {
+		if n > -math.MinInt32 {
+			p.errAt(ErrOverflow)
+		}
+		return int32(-int64(n))
+	}
+	if n > math.MaxInt32 {
+		p.errAt(ErrOverflow)
 	}
-	p.reset()
 	return int32(n)
 }
 
 // 解析int64
 func (p *Parser) Int64() int64 {
-	if p.token.kind == tokenUnknown {
-		p.getToken()
-	}
-	if p.token.kind != tokenNumber {
-		p.syntaxErr()
+	n, neg := p.integer()
+	if neg {
+		if n > 1<<63 {
+			p.errAt(ErrOverflow)
+		}
+		return -int64(n)
 	}
-	n, err := strconv.ParseInt(buffer.Bytes2Str(p.token.raw), 10, 64)
-	if err != nil {
-		panic(err)
+	if n > math.MaxInt64 {
+		p.errAt(ErrOverflow)
 	}
-	p.reset()
-	return n
+	return int64(n)
 }
 
 // 解析uint32
 func (p *Parser) Uint32() uint32 {
-	if p.token.kind == tokenUnknown {
-		p.getToken()
+	n, neg := p.integer()
+	if (neg && n != 0) || n > math.MaxUint32 {
+		p.errAt(ErrOverflow)
 	}
-	if p.token.kind != tokenNumber {
-		p.syntaxErr()
-	}
-	n, err := strconv.ParseUint(buffer.Bytes2Str(p.token.raw), 10, 32)
-	if err != nil {
-		panic(err)
-	}
-	p.reset()
 	return uint32(n)
 }
 
 // 解析uint64
 func (p *Parser) Uint64() uint64 {
-	if p.token.kind == tokenUnknown {
-		p.getToken()
+	n, neg := p.integer()
+	if neg && n != 0 {
+		p.errAt(ErrOverflow)
 	}
-	if p.token.kind != tokenNumber {
-		p.syntaxErr()
+	return n
+}
+
+// 解析浮点数，兼容用字符串表示的数字以及"NaN"、"Infinity"、"-Infinity"
+func (p *Parser) float(bitSize int) float64 {
+	raw, quoted := p.numberToken()
+	if quoted {
+		switch buffer.Bytes2Str(raw) {
+		case "NaN":
+			return math.NaN()
+		case "Infinity":
+			return math.Inf(1)
+		case "-Infinity":
+			return math.Inf(-1)
+		}
+		if end, ok := scanNumber(raw, 0); !ok || end != len(raw) {
+			p.errAt(ErrNumber)
+		}
 	}
-	n, err := strconv.ParseUint(buffer.Bytes2Str(p.token.raw), 10, 64)
+	// 语法已经校验过，只可能是超出范围
+	n, err := strconv.ParseFloat(buffer.Bytes2Str(raw), bitSize)
 	if err != nil {
-		panic(err)
+		p.errAt(ErrOverflow)
 	}
-	p.reset()
 	return n
 }
 
 // 解析float32
 func (p *Parser) Float32() float32 {
-	if p.token.kind == tokenUnknown {
-		p.getToken()
-	}
-	if p.token.kind != tokenNumber {
-		p.syntaxErr()
-	}
-	n, err := strconv.ParseFloat(buffer.Bytes2Str(p.token.raw), 32)
-	if err != nil {
-		panic(err)
-	}
-	p.reset()
-	return float32(n)
+	return float32(p.float(32))
 }
 
 // 解析float64
 func (p *Parser) Float64() float64 {
-	if p.token.kind == tokenUnknown {
-		p.getToken()
-	}
-	if p.token.kind != tokenNumber {
-		p.syntaxErr()
-	}
-	n, err := strconv.ParseFloat(buffer.Bytes2Str(p.token.raw), 64)
-	if err != nil {
-		panic(err)
-	}
-	p.reset()
-	return n
+	return p.float(64)
 }
 
 // 解析bytes
@@ -377,7 +811,26 @@ func (p *Parser) Bytes() []byte {
 		p.syntaxErr()
 	}
 	p.reset()
-	return p.token.raw
+	// 与protojson一致，兼容标准、URL两种字符集，padding可省略
+	raw := p.token.raw
+	url := bytes.ContainsAny(raw, "-_")
+	var enc *base64.Encoding
+	switch {
+	case len(raw)%4 != 0 && url:
+		enc = base64.RawURLEncoding
+	case len(raw)%4 != 0:
+		enc = base64.RawStdEncoding
+	case url:
+		enc = base64.URLEncoding
+	default:
+		enc = base64.StdEncoding
+	}
+	b := p.alloc(enc.DecodedLen(len(raw)))
+	n, err := enc.Decode(b, raw)
+	if err != nil {
+		p.errAt(ErrBase64)
+	}
+	return b[:n:n]
 }
 
 // 解析enum，兼容数字、字符串两种
@@ -387,7 +840,8 @@ func (p *Parser) Enum() (int, string, int32) {
 	}
 	switch p.token.kind {
 	case tokenString:
-		return EnumString, p.Str(), 0
+		// 只用于查找枚举值，不需要拷贝
+		return EnumString, p.Key(), 0
 	case tokenNumber:
 		return EnumNumber, "", p.Int32()
 	default:
@@ -408,6 +862,25 @@ func (p *Parser) Null() interface{} {
 	return nil
 }
 
+// 设置NullAsUnset且下一个值为null时跳过并返回true，供生成代码将null作为未设置的字段处理
+func (p *Parser) SkipNull() bool {
+	if !p.opts.NullAsUnset {
+		return false
+	}
+	if p.token.kind == tokenUnknown {
+		// 不是null时不读取token，之后仍然可以通过RawValue获取value的原始数据
+		if i := p.valueStart(); i >= len(p.data) || p.data[i] != 'n' {
+			return false
+		}
+		p.getToken()
+	}
+	if p.token.kind != tokenNull {
+		return false
+	}
+	p.reset()
+	return true
+}
+
 func (p *Parser) Symbol(b byte) {
 	if p.token.kind == tokenUnknown {
 		p.getToken()
@@ -418,13 +891,44 @@ func (p *Parser) Symbol(b byte) {
 	p.reset()
 }
 
+// 跳过空白字符以及未读取的分隔符（冒号或逗号），返回value开始的位置
+func (p *Parser) valueStart() int {
+	i, sep := p.off, p.assert
+	for ; i < len(p.data); i++ {
+		switch c := p.data[i]; c {
+		case ' ', '\t', '\r', '\n':
+		default:
+			if sep == 0 || c != sep {
+				return i
+			}
+			sep = 0
+		}
+	}
+	return i
+}
+
+// 跳过下一个value并返回其原始数据，返回的数据引用输入数据
+func (p *Parser) RawValue() []byte {
+	if p.token.kind != tokenUnknown {
+		p.syntaxErr()
+	}
+	i := p.valueStart()
+	p.PassParse()
+	return p.data[i:p.off]
+}
+
+// 供生成代码使用，是否忽略未知字段
+func (p *Parser) DiscardUnknown() bool {
+	return !p.opts.RejectUnknown
+}
+
 // 解析对象
 func (p *Parser) obj() map[string]interface{} {
 	ret := map[string]interface{}{}
 	for !p.IsSymbol('}') {
 		key := p.Str()
 		p.AssertSymbol(':')
-		ret[key] = p.Parse()
+		ret[key] = p.parse()
 		p.AssertSymbol(',')
 	}
 	// 处理空对象情况
@@ -437,7 +941,7 @@ func (p *Parser) obj() map[string]interface{} {
 func (p *Parser) arr() []interface{} {
 	ret := []interface{}{}
 	for !p.IsSymbol(']') {
-		ret = append(ret, p.Parse())
+		ret = append(ret, p.parse())
 		p.AssertSymbol(',')
 	}
 	// 处理空数组情况
@@ -449,7 +953,7 @@ func (p *Parser) arr() []interface{} {
 // 解析对象
 func (p *Parser) passObj() {
 	for !p.IsSymbol('}') {
-		p.Str()
+		p.Key()
 		p.AssertSymbol(':')
 		p.PassParse()
 		p.AssertSymbol(',')
@@ -470,8 +974,45 @@ func (p *Parser) passArr() {
 	p.Symbol(']')
 }
 
+// 判断下一个非空白字符是否为b
 func (p *Parser) IsSymbol(b byte) bool {
-	return p.data[p.off] == b
+	p.skipSpace()
+	return p.off < len(p.data) && p.data[p.off] == b
+}
+
+func (p *Parser) skipSpace() {
+	for p.off < len(p.data) {
+		switch p.data[p.off] {
+		case ' ', '\t', '\r', '\n':
+			p.off++
+		default:
+			return
+		}
+	}
+}
+
+// 检查输入已经完整解析，顶层值之后只允许出现空白字符
+func (p *Parser) End() {
+	if p.token.kind != tokenUnknown || len(p.frames) != 0 {
+		p.syntaxErr()
+	}
+	p.skipSpace()
+	if p.off < len(p.data) {
+		p.syntaxErr()
+	}
+}
+
+// 判断data是否为合法的json
+func Valid(data []byte) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	p := New(data)
+	p.PassParse()
+	p.End()
+	return true
 }
 
 func (p *Parser) reset() {
@@ -482,9 +1023,49 @@ func (p *Parser) AssertSymbol(b byte) {
 	p.assert = b
 }
 
+// 是否需要检查重复key
+func (p *Parser) CheckDuplicate() bool {
+	return p.opts.Duplicate != DuplicateLastWins
+}
+
+// 供生成代码使用，seen记录已经出现过的字段，i为字段的序号
+// 返回true表示已经跳过该字段的值
+func (p *Parser) SkipDuplicate(seen []uint64, i int, key string) bool {
+	if p.opts.Duplicate == DuplicateLastWins {
+		return false
+	}
+	w, b := i/64, uint64(1)<<(uint(i)%64)
+	if seen[w]&b == 0 {
+		seen[w] |= b
+		return false
+	}
+	return p.SkipDuplicateKey(key)
+}
+
+// 处理已经出现过的key，返回true表示已经跳过该key的值
+func (p *Parser) SkipDuplicateKey(key string) bool {
+	switch p.opts.Duplicate {
+	case DuplicateFirstWins:
+		p.PassParse()
+		return true
+	case DuplicateError:
+		panic(fmt.Errorf("%w: %q near col %d", ErrDuplicateKey, key, p.off))
+	}
+	return false
+}
+
+func (p *Parser) errAt(err error) {
+	panic(fmt.Errorf("%w: near col %d", err, p.off))
+}
+
 func (p *Parser) syntaxErr() {
 	if p.off < len(p.data) {
-		panic(`syntax error: near col ` + strconv.FormatInt(int64(p.off), 10) + ` "` + string(p.data[p.off:]) + `"`)
+		// 只展示出错位置附近的数据
+		near := p.data[p.off:]
+		if len(near) > 32 {
+			near = near[:32]
+		}
+		panic(`syntax error: near col ` + strconv.FormatInt(int64(p.off), 10) + ` "` + string(near) + `"`)
 	}
 	panic(`syntax error: near col ` + strconv.FormatInt(int64(p.off), 10))
 }
@@ -495,7 +1076,10 @@ func (p *Parser) Parse() interface{} {
 			fmt.Println(r)
 		}
 	}()
+	return p.parse()
+}
 
+func (p *Parser) parse() interface{} {
 	// 尝试获取新token，需要清空上一次token信息
 	if p.token.kind != tokenUnknown {
 		panic("system exception")
@@ -524,14 +1108,8 @@ func (p *Parser) Parse() interface{} {
 	panic("unknown token")
 }
 
-// 只解析不返回数据
+// 只解析不返回数据，错误通过panic返回给上层
 func (p *Parser) PassParse() {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Println(r)
-		}
-	}()
-
 	// 尝试获取新token，需要清空上一次token信息
 	if p.token.kind != tokenUnknown {
 		panic("system exception")
@@ -543,9 +1121,10 @@ func (p *Parser) PassParse() {
 	case tokenBool:
 		p.Bol()
 	case tokenString:
-		p.Str()
+		p.Key()
 	case tokenNumber:
-		p.Number()
+		// 语法已经在getToken中检查，不需要转换
+		p.reset()
 	case tokenNull:
 		p.Null()
 	case tokenSymbol:
@@ -554,6 +1133,8 @@ func (p *Parser) PassParse() {
 			p.passObj()
 		} else if p.token.symbol == '[' {
 			p.passArr()
+		} else {
+			p.syntaxErr()
 		}
 	default:
 		panic("unknown token")
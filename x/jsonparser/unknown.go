@@ -0,0 +1,151 @@
+package jsonparser
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/superjsf2010/protoc-gen-fastjsonpb/x/buffer"
+)
+
+// 跳过未知字段key的value，返回value的原始数据，RejectUnknown时返回错误
+// 返回的数据引用输入数据，需要拷贝后使用
+func (p *Parser) UnknownValue(key string) []byte {
+	if p.opts.RejectUnknown {
+		p.Unknown(key)
+	}
+	raw := p.RawValue()
+	if p.opts.OnUnknownField != nil {
+		p.opts.OnUnknownField(p.Path(), key, raw)
+	}
+	return raw
+}
+
+// 底层数组头部预留的字节数，保证分配的大小不小于16字节，不会使用tiny allocator与其他对象合并
+const unknownPad = 16
+
+// UnknownTable 生成代码开启preserve_unknown时保存未知字段的原始JSON，每个message类型一个
+// 数据不写入protobuf的unknownFields，proto.Marshal的结果不受影响
+// 生成的struct由protoc-gen-go定义，无法增加字段，因此以message的unknownFields底层数组为key：
+// 第一次保存时为unknownFields分配专用的底层数组（长度、内容不变），
+// message被回收后底层数组随之回收，通过finalizer删除对应的数据
+// 数据只跟随unknownFields的底层数组：
+//   - 浅拷贝的message共用同一份数据
+//   - proto.Clone、proto.Marshal/Unmarshal、proto.Merge等会复制或重新分配unknownFields，已保存的JSON随之丢弃
+type UnknownTable struct {
+	m sync.Map
+}
+
+type unknownEntry struct {
+	// 按第一次出现的顺序保存，同一个key只保留最后一个value
+	fields []unknownField
+}
+
+type unknownField struct {
+	key   string
+	value []byte
+}
+
+func unknownKey(uf []byte) uintptr {
+	if cap(uf) == 0 {
+		return 0
+	}
+	return uintptr(unsafe.Pointer(&uf[:cap(uf)][0]))
+}
+
+func (t *UnknownTable) entry(uf []byte) *unknownEntry {
+	if k := unknownKey(uf); k != 0 {
+		if v, ok := t.m.Load(k); ok {
+			return v.(*unknownEntry)
+		}
+	}
+	return nil
+}
+
+// 保存未知字段，uf为message的unknownFields
+// key已经存在时（如Merge模式下再次出现）替换原来的value，输出中不会出现重复的key
+func (t *UnknownTable) Append(uf *[]byte, key string, value []byte) {
+	e := t.entry(*uf)
+	if e == nil {
+		// 多出的1字节容量保证追加二进制未知字段（至少2字节）时一定重新分配，不会与保存的JSON错配
+		n := len(*uf)
+		block := make([]byte, unknownPad+n+1)
+		b := block[unknownPad : unknownPad+n : unknownPad+n+1]
+		copy(b, *uf)
+		*uf = b
+		e = &unknownEntry{}
+		k := unknownKey(b)
+		t.m.Store(k, e)
+		runtime.SetFinalizer(&block[0], func(*byte) { t.m.Delete(k) })
+	}
+	// value引用输入数据，需要拷贝
+	value = append([]byte(nil), value...)
+	for i := range e.fields {
+		if e.fields[i].key == key {
+			e.fields[i].value = value
+			return
+		}
+	}
+	e.fields = append(e.fields, unknownField{key: string([]byte(key)), value: value})
+}
+
+// 将保存的未知字段写回，每个字段后追加逗号
+// key、string按buf的选项重新转义（EscapeHTML、ASCIIOnly），设置StrictUTF8时非法UTF-8报告错误
+func (t *UnknownTable) Marshal(buf *buffer.Buffer, uf []byte) {
+	e := t.entry(uf)
+	if e == nil {
+		return
+	}
+	for _, f := range e.fields {
+		buf.WriteStringWithQuote(f.key)
+		buf.WriteByte(':')
+		New(f.value).writeValue(buf)
+		buf.WriteByte(',')
+	}
+}
+
+// 清空保存的未知字段，unknownFields中二进制格式的未知字段不受影响
+func (t *UnknownTable) Reset(uf []byte) {
+	if e := t.entry(uf); e != nil {
+		e.fields = e.fields[:0]
+	}
+}
+
+// 将下一个value重新写入buf，去掉空白，string按buf的选项重新转义
+// 保存的数据在解析时已经检查过语法
+func (p *Parser) writeValue(buf *buffer.Buffer) {
+	p.getToken()
+	kind := p.token.kind
+	p.reset()
+	switch kind {
+	case tokenString:
+		buf.WriteStringWithQuote(buffer.Bytes2Str(p.token.raw))
+	case tokenSymbol:
+		end := byte(']')
+		if p.token.symbol == '{' {
+			end = '}'
+		}
+		buf.WriteByte(p.token.symbol)
+		for i := 0; !p.IsSymbol(end); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if end == '}' {
+				buf.WriteStringWithQuote(p.Key())
+				buf.WriteByte(':')
+				p.AssertSymbol(':')
+			}
+			p.writeValue(buf)
+			p.AssertSymbol(',')
+		}
+		p.AssertSymbol(',')
+		p.Symbol(end)
+		buf.WriteByte(end)
+	case tokenNumber:
+		buf.Write(p.token.raw)
+	case tokenBool:
+		buf.WriteBool(p.token.bol)
+	case tokenNull:
+		buf.WriteStr("null")
+	}
+}